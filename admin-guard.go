@@ -0,0 +1,88 @@
+package skeleton
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+)
+
+// AdminGuard protects administrative endpoints (e.g., /metrics,
+// /debug/pprof, maintenance toggles) independently of the authentication of
+// the application. A request is allowed if it satisfies any of the
+// configured methods:
+//
+//   - its client IP belongs to AllowedNetworks;
+//   - it carries Token as a bearer token;
+//   - it carries Username and Password using basic auth.
+//
+// If no method is configured, or the guard is nil, every request is
+// rejected.
+type AdminGuard struct {
+	Token    string
+	Username string
+	Password string
+
+	// AllowedNetworks are the networks from which requests are allowed
+	// without credentials. See ParseCIDRs.
+	AllowedNetworks []*net.IPNet
+
+	// TrustedProxies is used to determine the client IP of the request. See
+	// ClientIP.
+	TrustedProxies TrustedProxies
+}
+
+// Allow returns true if the request may access the admin endpoints.
+func (g *AdminGuard) Allow(r *http.Request) bool {
+	if g == nil {
+		return false
+	}
+	if len(g.AllowedNetworks) > 0 {
+		if TrustedProxies(g.AllowedNetworks).Trusts(ClientIP(r, g.TrustedProxies)) {
+			return true
+		}
+	}
+	if g.Token != "" {
+		if token := BearerToken(r); token != "" && secureCompare(token, g.Token) {
+			return true
+		}
+	}
+	if g.Username != "" && g.Password != "" {
+		user, pass, ok := r.BasicAuth()
+		// Both are compared, so that the time taken does not reveal which
+		// was incorrect.
+		userOk := secureCompare(user, g.Username)
+		passOk := secureCompare(pass, g.Password)
+		if ok && userOk && passOk {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler is a middleware which rejects requests that are not allowed. A 401
+// is returned if credentials are configured, otherwise a 403.
+func (g *AdminGuard) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g.Allow(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		switch {
+		case g == nil:
+			w.WriteHeader(http.StatusForbidden)
+		case g.Username != "" && g.Password != "":
+			w.Header().Set("WWW-Authenticate", `Basic realm="admin", charset="UTF-8"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case g.Token != "":
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	})
+}
+
+// secureCompare compares the strings in constant time.
+func secureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
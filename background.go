@@ -0,0 +1,143 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrBackgroundClosed = errors.New("background tasks are shutting down")
+)
+
+// DefaultBackgroundDrainTimeout is the time Background.Routine waits for tasks
+// to complete on shutdown when Background.DrainTimeout is not set.
+const DefaultBackgroundDrainTimeout = 5 * time.Second
+
+// BackgroundMode determines what a background task is tied to, and therefore
+// when its context is canceled.
+type BackgroundMode int
+
+const (
+	// BackgroundServer ties the task to the server. The task survives the
+	// request which enqueued it and is only canceled if it has not completed
+	// by the end of the shutdown drain.
+	BackgroundServer BackgroundMode = iota
+
+	// BackgroundRequest ties the task to the request which enqueued it. The
+	// task is canceled as soon as the request context is done, which happens
+	// when the client disconnects or once the request has been served. It
+	// is also canceled at the end of the shutdown drain.
+	BackgroundRequest
+)
+
+// Background runs tasks outside of the request-response cycle (e.g., sending
+// an email after a sign-up) and drains them on shutdown.
+//
+// On shutdown, new tasks are rejected and running tasks (of both modes) are
+// given until the drain deadline to complete. Tasks which have not completed
+// by then have their context canceled. Tasks should therefore watch their
+// context to stop promptly.
+//
+// Use Background.Routine with Run so that draining happens as part of the
+// server shutdown.
+type Background struct {
+	// DrainTimeout is the time Routine waits for tasks to complete on
+	// shutdown. Defaults to DefaultBackgroundDrainTimeout.
+	DrainTimeout time.Duration
+
+	initOnce sync.Once
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewBackground creates a Background with the provided drain timeout.
+func NewBackground(drainTimeout time.Duration) *Background {
+	return &Background{DrainTimeout: drainTimeout}
+}
+
+func (b *Background) init() {
+	b.initOnce.Do(func() {
+		b.ctx, b.cancel = context.WithCancel(context.Background())
+	})
+}
+
+// Go runs fn in a new goroutine. The mode determines what fn's context is
+// tied to; reqCtx is the context of the request enqueuing the task, and is
+// only used for BackgroundRequest. ErrBackgroundClosed is returned if
+// shutdown has begun, in which case fn is not run.
+func (b *Background) Go(reqCtx context.Context, mode BackgroundMode, fn func(ctx context.Context)) error {
+	b.init()
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrBackgroundClosed
+	}
+	b.wg.Add(1)
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(b.ctx)
+	if mode == BackgroundRequest {
+		go func() {
+			select {
+			case <-reqCtx.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	go func() {
+		defer b.wg.Done()
+		defer cancel()
+		fn(ctx)
+	}()
+	return nil
+}
+
+// Shutdown rejects new tasks and waits for running tasks to complete. If ctx
+// is done first, the remaining tasks are canceled and ctx.Err() is returned.
+func (b *Background) Shutdown(ctx context.Context) error {
+	b.init()
+
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// Routine returns a RunRoutine which drains the background tasks once the
+// server shuts down, waiting at most DrainTimeout.
+func (b *Background) Routine() RunRoutine {
+	return func(stop <-chan struct{}) {
+		<-stop
+
+		timeout := b.DrainTimeout
+		if timeout <= 0 {
+			timeout = DefaultBackgroundDrainTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_ = b.Shutdown(ctx)
+	}
+}
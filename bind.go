@@ -0,0 +1,331 @@
+package skeleton
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+var (
+	ErrInvalidBindTarget = errors.New("bind target must be a pointer to a struct")
+)
+
+// FieldError describes a single field which failed to bind or validate.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is returned when binding or validation of a request fails.
+// It maps to a 400 Bad Request through ErrorStatus.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, f := range e {
+		if f.Field == "" {
+			msgs = append(msgs, f.Message)
+			continue
+		}
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// StatusCode returns http.StatusBadRequest. It implements StatusCoder.
+func (e ValidationErrors) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// Validator can be implemented by bind targets to provide custom validation,
+// which is run by Bind after all values have been populated. If a
+// ValidationErrors is returned, its entries are merged into the result.
+type Validator interface {
+	Validate() error
+}
+
+// Bind populates v, which must be a pointer to a struct, from the request
+// represented by the provided context. Values are populated in the following
+// order, with later sources overriding earlier ones:
+//
+//  1. the JSON body, based on `json` tags;
+//  2. query parameters, based on `query` tags;
+//  3. path parameters, based on `param` tags.
+//
+// Once populated, fields tagged with `validate:"required"` are checked to be
+// non-zero, and Validator.Validate is called if implemented. Any failure
+// results in a ValidationErrors.
+//
+// ```
+//
+//	type UpdateUser struct {
+//	    ID   string `param:"id" validate:"required"`
+//	    Page int    `query:"page"`
+//	    Name string `json:"name" validate:"required"`
+//	}
+//
+// ```
+func Bind(ctx *gorouter.RouteContext, v interface{}) error {
+	var errs ValidationErrors
+	if err := BindBody(ctx.R, v); err != nil {
+		if !appendValidationErrors(&errs, err) {
+			return err
+		}
+	}
+	if ctx.R != nil {
+		if err := BindQuery(ctx.R.URL.Query(), v); err != nil {
+			if !appendValidationErrors(&errs, err) {
+				return err
+			}
+		}
+	}
+	if err := BindParams(ctx.Params, v); err != nil {
+		if !appendValidationErrors(&errs, err) {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return Validate(v)
+}
+
+// Validate checks fields tagged with `validate:"required"` and calls
+// Validator.Validate if v implements it.
+func Validate(v interface{}) error {
+	rv, err := bindTarget(v)
+	if err != nil {
+		return err
+	}
+
+	var errs ValidationErrors
+	validateRequired(rv, &errs)
+
+	if vd, ok := v.(Validator); ok {
+		if err := vd.Validate(); err != nil {
+			if !appendValidationErrors(&errs, err) {
+				errs = append(errs, FieldError{Message: err.Error()})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// BindBody decodes the JSON body of the request into v. An empty body is not
+// considered an error. As with DecodeBody, read errors which carry their own
+// status (e.g., a 413 from Decompress) are returned unchanged.
+func BindBody(r *http.Request, v interface{}) error {
+	if r == nil || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		var sc StatusCoder
+		if errors.As(err, &sc) {
+			return err
+		}
+		return ValidationErrors{{Message: "could not read body: " + err.Error()}}
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	if err := DefaultJSONCodec.Unmarshal(b, v); err != nil {
+		return ValidationErrors{{Message: "invalid JSON body: " + err.Error()}}
+	}
+	return nil
+}
+
+// BindQuery populates fields of v tagged with `query` from the provided query
+// values. Slice fields receive all values of a parameter.
+func BindQuery(query url.Values, v interface{}) error {
+	return bindValues("query", v, func(name string) ([]string, bool) {
+		vals, ok := query[name]
+		return vals, ok
+	})
+}
+
+// BindParams populates fields of v tagged with `param` from the provided path
+// parameters.
+func BindParams(params map[string]string, v interface{}) error {
+	return bindValues("param", v, func(name string) ([]string, bool) {
+		val, ok := params[name]
+		if !ok {
+			return nil, false
+		}
+		return []string{val}, true
+	})
+}
+
+// appendValidationErrors appends err to errs if it is a ValidationErrors. It
+// returns false otherwise.
+func appendValidationErrors(errs *ValidationErrors, err error) bool {
+	var ve ValidationErrors
+	if !errors.As(err, &ve) {
+		return false
+	}
+	*errs = append(*errs, ve...)
+	return true
+}
+
+// bindTarget ensures that v is a pointer to a struct and returns the struct.
+func bindTarget(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, ErrInvalidBindTarget
+	}
+	return rv.Elem(), nil
+}
+
+// bindValues populates all fields of v with the provided tag using the values
+// returned by lookup.
+func bindValues(tag string, v interface{}, lookup func(name string) ([]string, bool)) error {
+	rv, err := bindTarget(v)
+	if err != nil {
+		return err
+	}
+	var errs ValidationErrors
+	bindStruct(rv, tag, lookup, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func bindStruct(rv reflect.Value, tag string, lookup func(name string) ([]string, bool), errs *ValidationErrors) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+
+		// Embedded structs are bound as if their fields belonged to the
+		// parent.
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			bindStruct(fv, tag, lookup, errs)
+			continue
+		}
+
+		name := tagName(field, tag)
+		if name == "" || !fv.CanSet() {
+			continue
+		}
+		vals, ok := lookup(name)
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		if err := setField(fv, vals); err != nil {
+			*errs = append(*errs, FieldError{Field: name, Message: err.Error()})
+		}
+	}
+}
+
+// tagName returns the name provided by the tag, ignoring any options such as
+// omitempty.
+func tagName(field reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// setField converts the provided values into the type of fv and sets it.
+func setField(fv reflect.Value, vals []string) error {
+	if fv.Kind() == reflect.Pointer {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setField(ptr.Elem(), vals); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	if fv.CanAddr() {
+		if tu, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return tu.UnmarshalText([]byte(vals[0]))
+		}
+	}
+
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setField(slice.Index(i), []string{val}); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	val := vals[0]
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("%q is not a valid boolean", val)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid integer", val)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid unsigned integer", val)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid number", val)
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
+
+// validateRequired checks that every field tagged with `validate:"required"`
+// is non-zero.
+func validateRequired(rv reflect.Value, errs *ValidationErrors) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			validateRequired(fv, errs)
+			continue
+		}
+		if field.Tag.Get("validate") != "required" || !fv.IsZero() {
+			continue
+		}
+		*errs = append(*errs, FieldError{Field: fieldName(field), Message: "is required"})
+	}
+}
+
+// fieldName returns the name used to refer to a field in errors, preferring
+// the names provided by the binding tags.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "param"} {
+		if name := tagName(field, tag); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,122 @@
+package skeleton
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+)
+
+// DefaultBodyMemoryThreshold is the number of bytes of a request body which
+// are buffered in memory before spilling to a temporary file.
+const DefaultBodyMemoryThreshold = 1 << 20
+
+var (
+	ErrBodyTooLarge = errors.New("request body is too large")
+)
+
+// bodyBuffer holds a copy of a request body, so that the server can read the
+// body before the handler (currently, only to find the method override form
+// field) without consuming it. Small bodies are held in memory; larger
+// bodies spill to a temporary file, which is removed by Close.
+//
+// Readers which consume the body on behalf of the handler (e.g., DecodeBody
+// and BindBody) do not need to restore it, and Decompress streams the body,
+// so they do not buffer it.
+type bodyBuffer struct {
+	mem  []byte
+	file *os.File
+	size int64
+}
+
+// bufferBody reads the body of the request, up to maxBytes, and replaces
+// r.Body with a reader over the buffered copy. A maxBytes of 0 or less does
+// not limit the size of the body. The returned buffer must be closed once
+// the request has been served, to remove any temporary file.
+//
+// If the body exceeds maxBytes, a 413 *HttpError is returned. If reading
+// fails part way (e.g., the client disconnects), a 400 *HttpError is
+// returned, unless the error carries its own status. In both cases, the
+// body has been partially consumed and cannot be restored, so the request
+// should be rejected.
+func bufferBody(r *http.Request, maxBytes int64) (*bodyBuffer, error) {
+	buf := &bodyBuffer{}
+	if r.Body == nil || r.Body == http.NoBody {
+		return buf, nil
+	}
+
+	var src io.Reader = r.Body
+	if maxBytes > 0 {
+		src = io.LimitReader(r.Body, maxBytes+1)
+	}
+
+	mem, err := io.ReadAll(io.LimitReader(src, DefaultBodyMemoryThreshold))
+	if err != nil {
+		return nil, readBodyError(err)
+	}
+	buf.mem = mem
+	buf.size = int64(len(mem))
+
+	// Spill the remainder, if any, to a temporary file.
+	if len(mem) == DefaultBodyMemoryThreshold {
+		file, err := os.CreateTemp("", "skeleton-body-*")
+		if err != nil {
+			return nil, err
+		}
+		buf.file = file
+		n, err := io.Copy(file, src)
+		buf.size += n
+		if err != nil {
+			buf.Close()
+			return nil, readBodyError(err)
+		}
+	}
+
+	if maxBytes > 0 && buf.size > maxBytes {
+		buf.Close()
+		return nil, NewHttpError(http.StatusRequestEntityTooLarge, ErrBodyTooLarge.Error(), ErrBodyTooLarge)
+	}
+
+	r.Body = buf.Reader()
+	r.ContentLength = buf.size
+	return buf, nil
+}
+
+// readBodyError converts an error reading the body into a 400 *HttpError.
+// Errors carrying a status (e.g., a body exceeding HttpServer.MaxBodyBytes)
+// are returned as is.
+func readBodyError(err error) error {
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		return err
+	}
+	return NewHttpError(http.StatusBadRequest, "could not read request body", err)
+}
+
+// Reader returns a new reader over the buffered body.
+func (b *bodyBuffer) Reader() io.ReadCloser {
+	if b.file == nil {
+		return io.NopCloser(bytes.NewReader(b.mem))
+	}
+	fileSize := b.size - int64(len(b.mem))
+	return io.NopCloser(io.MultiReader(
+		bytes.NewReader(b.mem),
+		io.NewSectionReader(b.file, 0, fileSize),
+	))
+}
+
+// Close removes the temporary file, if any. It is safe to call more than
+// once.
+func (b *bodyBuffer) Close() error {
+	if b == nil || b.file == nil {
+		return nil
+	}
+	name := b.file.Name()
+	err := b.file.Close()
+	b.file = nil
+	if rmErr := os.Remove(name); err == nil {
+		err = rmErr
+	}
+	return err
+}
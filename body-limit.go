@@ -0,0 +1,46 @@
+package skeleton
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// limitedBody limits the size of a request body with http.MaxBytesReader.
+// Exceeding the limit results in a 413 *HttpError wrapping ErrBodyTooLarge,
+// so that it is reported consistently by DecodeBody and ErrorStatus.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+// limitBody replaces the body of the request with one limited to maxBytes.
+// Requests whose Content-Length already exceeds the limit are rejected
+// without reading the body.
+func limitBody(w http.ResponseWriter, r *http.Request, maxBytes int64) (*limitedBody, error) {
+	if r.ContentLength > maxBytes {
+		return nil, NewHttpError(http.StatusRequestEntityTooLarge, ErrBodyTooLarge.Error(), ErrBodyTooLarge)
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes)}
+	r.Body = body
+	return body, nil
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var mErr *http.MaxBytesError
+	if errors.As(err, &mErr) {
+		b.exceeded = true
+		err = NewHttpError(http.StatusRequestEntityTooLarge, ErrBodyTooLarge.Error(), ErrBodyTooLarge)
+	}
+	return n, err
+}
+
+// Exceeded returns true if a read exceeded the limit. It is safe to call on a
+// nil limitedBody.
+func (b *limitedBody) Exceeded() bool {
+	return b != nil && b.exceeded
+}
@@ -0,0 +1,282 @@
+package skeleton
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	EncodingBrotli  = "br"
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+
+	// DefaultBrotliLevel is the Brotli level used when
+	// CompressionOptions.BrotliLevel is not set.
+	DefaultBrotliLevel = 6
+)
+
+// BrotliEncoderFunc creates a Brotli writer with the provided level. Brotli is
+// not part of the standard library, so an implementation needs to be
+// provided by the application. For example, using
+// github.com/andybalholm/brotli:
+//
+// ```
+//
+//	func(w io.Writer, level int) io.WriteCloser {
+//	    return brotli.NewWriterLevel(w, level)
+//	}
+//
+// ```
+type BrotliEncoderFunc func(w io.Writer, level int) io.WriteCloser
+
+// CompressionOptions configures Compress.
+type CompressionOptions struct {
+	// GzipLevel and DeflateLevel are the compression levels as defined by
+	// compress/flate. A value of 0 uses flate.DefaultCompression.
+	GzipLevel    int
+	DeflateLevel int
+
+	// BrotliLevel is the Brotli compression level. A value of 0 uses
+	// DefaultBrotliLevel.
+	BrotliLevel int
+
+	// BrotliEncoder enables Brotli. If nil, Brotli is never negotiated.
+	BrotliEncoder BrotliEncoderFunc
+}
+
+// Compress returns a middleware which compresses responses based on the
+// Accept-Encoding header of the request. The encoding with the highest
+// quality value is chosen. When quality values are equal, Brotli is
+// preferred, followed by gzip and then deflate. Brotli is only considered if
+// an encoder is provided.
+//
+// Vary: Accept-Encoding is always set. Responses which already contain a
+// Content-Encoding header, responses to HEAD requests and responses without
+// a body are left untouched.
+func Compress(opts CompressionOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), opts.BrotliEncoder != nil)
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				opts:           opts,
+			}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the encoding which should be used for the
+// provided Accept-Encoding header, or an empty string if the response should
+// not be compressed.
+func negotiateEncoding(header string, brotli bool) string {
+	preference := []string{EncodingGzip, EncodingDeflate}
+	if brotli {
+		preference = []string{EncodingBrotli, EncodingGzip, EncodingDeflate}
+	}
+
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(k) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = parsed
+			}
+		}
+		qualities[coding] = q
+	}
+
+	candidates := make([]string, 0, len(preference))
+	for _, coding := range preference {
+		q, ok := qualities[coding]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+		if ok && q > 0 {
+			candidates = append(candidates, coding)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	// A stable sort keeps the preference order for equal quality values.
+	quality := func(coding string) float64 {
+		if q, ok := qualities[coding]; ok {
+			return q
+		}
+		return qualities["*"]
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return quality(candidates[i]) > quality(candidates[j])
+	})
+	return candidates[0]
+}
+
+// compressResponseWriter compresses everything written to it. The decision to
+// compress is made on the first Write, so that responses without a body are
+// not encoded.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	opts     CompressionOptions
+
+	status      int
+	wroteHeader bool
+	encoder     io.WriteCloser
+}
+
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader || w.status != 0 {
+		return
+	}
+	if status >= 100 && status < 200 {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.start(b)
+	}
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.encoder.Write(b)
+}
+
+// start writes the header, deciding whether the body should be encoded. The
+// first chunk of the body is used to detect the content type if it has not
+// been set, as detection cannot be performed on the encoded bytes.
+func (w *compressResponseWriter) start(first []byte) {
+	w.wroteHeader = true
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	h := w.Header()
+	if h.Get("Content-Encoding") == "" && bodyAllowedForStatus(status) {
+		if _, ok := h["Content-Type"]; !ok && len(first) > 0 {
+			h.Set("Content-Type", http.DetectContentType(first))
+		}
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		w.encoder = w.newEncoder()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *compressResponseWriter) newEncoder() io.WriteCloser {
+	switch w.encoding {
+	case EncodingBrotli:
+		level := w.opts.BrotliLevel
+		if level == 0 {
+			level = DefaultBrotliLevel
+		}
+		return w.opts.BrotliEncoder(w.ResponseWriter, level)
+	case EncodingGzip:
+		gw, err := gzip.NewWriterLevel(w.ResponseWriter, compressionLevel(w.opts.GzipLevel))
+		if err != nil {
+			gw = gzip.NewWriter(w.ResponseWriter)
+		}
+		return gw
+	default:
+		fw, err := flate.NewWriter(w.ResponseWriter, compressionLevel(w.opts.DeflateLevel))
+		if err != nil {
+			fw, _ = flate.NewWriter(w.ResponseWriter, flate.DefaultCompression)
+		}
+		return fw
+	}
+}
+
+// Flush flushes the encoder (if any) and the underlying writer.
+func (w *compressResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.start(nil)
+	}
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack allows connection upgrades through the compressing writer.
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("underlying ResponseWriter does not implement http.Hijacker")
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close completes the response, writing any trailing bytes of the encoding.
+func (w *compressResponseWriter) Close() error {
+	if !w.wroteHeader {
+		if w.status == 0 {
+			return nil
+		}
+		// Only a header was written. Forward it without encoding.
+		w.wroteHeader = true
+		w.ResponseWriter.WriteHeader(w.status)
+		return nil
+	}
+	if w.encoder == nil {
+		return nil
+	}
+	return w.encoder.Close()
+}
+
+func compressionLevel(level int) int {
+	if level == 0 {
+		return flate.DefaultCompression
+	}
+	return level
+}
+
+// bodyAllowedForStatus reports whether a response with the provided status
+// is permitted to have a body.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package skeleton
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrencyLimiter is a semaphore which limits the number of requests that
+// can be processed at the same time.
+type ConcurrencyLimiter struct {
+	sem      chan struct{}
+	inFlight int64
+}
+
+// NewConcurrencyLimiter creates a limiter which admits at most max concurrent
+// requests. A max below 1 is treated as 1, as a limiter without capacity
+// would reject every request.
+func NewConcurrencyLimiter(max int) *ConcurrencyLimiter {
+	if max < 1 {
+		max = 1
+	}
+	return &ConcurrencyLimiter{
+		sem: make(chan struct{}, max),
+	}
+}
+
+// TryAcquire attempts to acquire a slot without waiting. It returns true if
+// a slot was acquired, in which case Release must be called once done.
+func (l *ConcurrencyLimiter) TryAcquire() bool {
+	select {
+	case l.sem <- struct{}{}:
+		atomic.AddInt64(&l.inFlight, 1)
+		return true
+	default:
+		return false
+	}
+}
+
+// Acquire waits up to timeout for a slot to be available. It returns false if
+// the timeout elapses or the context is cancelled first. A timeout of 0 is
+// the same as calling TryAcquire.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context, timeout time.Duration) bool {
+	if timeout <= 0 {
+		return l.TryAcquire()
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case l.sem <- struct{}{}:
+		atomic.AddInt64(&l.inFlight, 1)
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Release frees a slot acquired through TryAcquire or Acquire.
+func (l *ConcurrencyLimiter) Release() {
+	atomic.AddInt64(&l.inFlight, -1)
+	<-l.sem
+}
+
+// InFlight returns the number of slots which are currently acquired.
+func (l *ConcurrencyLimiter) InFlight() int64 {
+	return atomic.LoadInt64(&l.inFlight)
+}
+
+// Capacity returns the maximum number of concurrent slots.
+func (l *ConcurrencyLimiter) Capacity() int {
+	return cap(l.sem)
+}
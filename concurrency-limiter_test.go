@@ -0,0 +1,23 @@
+package skeleton
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewConcurrencyLimiterCapacity(t *testing.T) {
+	for _, max := range []int{-1, 0} {
+		l := NewConcurrencyLimiter(max)
+		if l.Capacity() != 1 {
+			t.Errorf("capacity of %d = %d, want 1", max, l.Capacity())
+		}
+	}
+}
+
+func TestLoadShedderZeroCapacity(t *testing.T) {
+	release, err := NewLoadShedder(0).admit(httptest.NewRecorder(), DefaultPriority)
+	if err != nil {
+		t.Fatalf("request was shed: %v", err)
+	}
+	release()
+}
@@ -0,0 +1,78 @@
+package skeleton
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// CorrelationIDHeader is the header carrying the correlation ID between
+// services.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// maxCorrelationIDLength bounds the length of incoming correlation IDs, as
+// they are supplied by the client and end up in every log entry.
+const maxCorrelationIDLength = 128
+
+type correlationIDKey struct{}
+
+// CorrelationID returns the correlation ID stored in the context, or an empty
+// string if there is none.
+//
+// The correlation ID ties together the logs of all services involved in an
+// operation, and is propagated unchanged between them. It differs from the
+// request ID (the request-id header of the LoggingHttpServer), which is
+// unique to each request received by each service. Where W3C trace context
+// (traceparent) is in use, the correlation ID complements it: the trace
+// identifies spans for tracing backends, whereas the correlation ID is a
+// simple key for searching logs.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// WithCorrelationID returns a copy of ctx carrying the provided correlation
+// ID.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// InjectCorrelationID sets the correlation ID of ctx on an outbound request
+// made by a handler, so that the next service continues the correlation.
+func InjectCorrelationID(ctx context.Context, out *http.Request) {
+	if id := CorrelationID(ctx); id != "" {
+		out.Header.Set(CorrelationIDHeader, id)
+	}
+}
+
+// correlate ensures that the request carries a correlation ID in its
+// context. The incoming CorrelationIDHeader is used if valid; otherwise a
+// new ID is generated. The ID is echoed in the response header.
+func correlate(w http.ResponseWriter, r *http.Request) *http.Request {
+	id := CorrelationID(r.Context())
+	if id != "" {
+		return r
+	}
+
+	id = r.Header.Get(CorrelationIDHeader)
+	if !validCorrelationID(id) {
+		id = uuid.New().String()
+	}
+	w.Header().Set(CorrelationIDHeader, id)
+	return r.WithContext(WithCorrelationID(r.Context(), id))
+}
+
+// validCorrelationID allows printable ASCII IDs of bounded length, so that
+// they cannot be used to inject content into logs or headers.
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
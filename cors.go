@@ -0,0 +1,156 @@
+package skeleton
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCORSMethods are the methods allowed in preflight responses when
+// CORSOptions.AllowedMethods is empty.
+var DefaultCORSMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// CORSOptions configures Cross-Origin Resource Sharing.
+type CORSOptions struct {
+	// AllowedOrigins are the origins which may access the server (e.g.,
+	// https://app.example.com). "*" allows any origin. If empty, no origin
+	// is allowed.
+	AllowedOrigins []string
+
+	// AllowedMethods are the methods allowed in preflight requests. Defaults
+	// to DefaultCORSMethods.
+	AllowedMethods []string
+
+	// AllowedHeaders are the request headers allowed in preflight requests.
+	// If empty, the headers requested by the preflight are allowed.
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers which browsers expose to the
+	// client (e.g., request-id).
+	ExposedHeaders []string
+
+	// AllowCredentials allows requests with credentials (e.g., cookies) from
+	// the origins listed in AllowedOrigins. It does not apply to origins
+	// which are only allowed through "*": those receive the wildcard without
+	// credentials, as reflecting any origin with credentials would let every
+	// site make authenticated requests.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache the preflight response.
+	MaxAge time.Duration
+}
+
+// allowOrigin returns the value of Access-Control-Allow-Origin for the
+// origin, or an empty string if it is not allowed. Listed origins are
+// reflected, while origins only allowed through "*" receive the wildcard.
+func (o CORSOptions) allowOrigin(origin string) string {
+	wildcard := false
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" {
+			wildcard = true
+			continue
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	if wildcard {
+		return "*"
+	}
+	return ""
+}
+
+// apply sets the CORS headers of the response. It returns true if the
+// request was a preflight, which has been answered with a 204.
+func (o CORSOptions) apply(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	h := w.Header()
+	h.Add("Vary", "Origin")
+
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+	allowOrigin := o.allowOrigin(origin)
+	if allowOrigin == "" {
+		if preflight {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return preflight
+	}
+
+	h.Set("Access-Control-Allow-Origin", allowOrigin)
+	if o.AllowCredentials && allowOrigin != "*" {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if !preflight {
+		if len(o.ExposedHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(o.ExposedHeaders, ", "))
+		}
+		return false
+	}
+
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	methods := o.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(o.AllowedHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(o.AllowedHeaders, ", "))
+	} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+		h.Set("Access-Control-Allow-Headers", requested)
+	}
+	if o.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(o.MaxAge.Seconds())))
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// CORS returns a Middleware which sets the CORS headers of responses, and
+// answers preflight requests with a 204 without calling the route handler.
+//
+// Middleware only runs for matched routes, so preflights only reach it for
+// paths with an OPTIONS route. To answer preflights for every path, wrap the
+// server with CORSOptions.Handler instead.
+//
+// ```
+//
+//	server.Use(skeleton.CORS(skeleton.CORSOptions{
+//	    AllowedOrigins:   []string{"https://app.example.com"},
+//	    AllowCredentials: true,
+//	}, skeleton.GoRouteContextAdapter))
+//
+// ```
+func CORS[Ctx any](opts CORSOptions, adapter ContextAdapter[Ctx]) Middleware[Ctx] {
+	return func(next func(Ctx)) func(Ctx) {
+		return func(ctx Ctx) {
+			w, r := adapter(ctx)
+			if opts.apply(w, r) {
+				return
+			}
+			next(ctx)
+		}
+	}
+}
+
+// Handler is an http.Handler middleware equivalent to CORS, which answers
+// preflight requests before they are routed.
+func (o CORSOptions) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if o.apply(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,145 @@
+package skeleton
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultMaxDecompressedBytes is the maximum size of a decompressed
+	// request body when DecompressionOptions.MaxBytes is not set.
+	DefaultMaxDecompressedBytes = 10 << 20
+
+	// DefaultMaxDecompressionRatio is the maximum ratio between the
+	// decompressed and compressed sizes of a request body when
+	// DecompressionOptions.MaxRatio is not set.
+	DefaultMaxDecompressionRatio = 100
+
+	// decompressionRatioGrace is the decompressed size below which the ratio
+	// is not enforced, as small bodies (e.g., of repeated characters) can
+	// legitimately have a high ratio.
+	decompressionRatioGrace = 64 << 10
+)
+
+// DecompressionOptions configures Decompress.
+type DecompressionOptions struct {
+	// MaxBytes is the maximum size of the decompressed body. A value of 0
+	// uses DefaultMaxDecompressedBytes.
+	MaxBytes int64
+
+	// MaxRatio is the maximum ratio between the decompressed and compressed
+	// sizes of the body. A value of 0 uses DefaultMaxDecompressionRatio; a
+	// negative value disables the check.
+	MaxRatio float64
+}
+
+// Decompress returns a middleware which transparently decompresses request
+// bodies sent with a Content-Encoding of gzip or deflate. Other encodings
+// are rejected with a 415.
+//
+// As a small compressed body can decompress to gigabytes (a "zip bomb"),
+// the decompressed output is capped by both an absolute size and a ratio to
+// the compressed size. Once either is exceeded, reading the body fails with
+// a 413 *HttpError wrapping ErrBodyTooLarge. The compressed size should
+// still be limited separately (e.g., with http.MaxBytesReader).
+func Decompress(opts DecompressionOptions) func(http.Handler) http.Handler {
+	if opts.MaxBytes == 0 {
+		opts.MaxBytes = DefaultMaxDecompressedBytes
+	}
+	if opts.MaxRatio == 0 {
+		opts.MaxRatio = DefaultMaxDecompressionRatio
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+			if encoding == "" || encoding == "identity" || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			compressed := &countingReader{r: r.Body}
+			var decompressor io.ReadCloser
+			switch encoding {
+			case EncodingGzip:
+				gr, err := gzip.NewReader(compressed)
+				if err != nil {
+					http.Error(w, "invalid gzip body", http.StatusBadRequest)
+					return
+				}
+				decompressor = gr
+			case EncodingDeflate:
+				decompressor = flate.NewReader(compressed)
+			default:
+				http.Error(w, "unsupported Content-Encoding", http.StatusUnsupportedMediaType)
+				return
+			}
+
+			r2 := r.Clone(r.Context())
+			r2.Body = &decompressedBody{
+				ReadCloser: decompressor,
+				original:   r.Body,
+				compressed: compressed,
+				opts:       opts,
+			}
+			r2.Header.Del("Content-Encoding")
+			r2.Header.Del("Content-Length")
+			r2.ContentLength = -1
+			next.ServeHTTP(w, r2)
+		})
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decompressedBody enforces the limits of DecompressionOptions on the output
+// of a decompressor.
+type decompressedBody struct {
+	io.ReadCloser
+	original   io.ReadCloser
+	compressed *countingReader
+	opts       DecompressionOptions
+	n          int64
+	err        error
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	// Read at most one byte past the limit, to detect that it was exceeded.
+	if remaining := b.opts.MaxBytes + 1 - b.n; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+
+	exceeded := b.n > b.opts.MaxBytes
+	if b.opts.MaxRatio > 0 && b.n > decompressionRatioGrace && b.compressed.n > 0 {
+		exceeded = exceeded || float64(b.n) > b.opts.MaxRatio*float64(b.compressed.n)
+	}
+	if exceeded {
+		b.err = NewHttpError(http.StatusRequestEntityTooLarge, "decompressed request body is too large", ErrBodyTooLarge)
+		return 0, b.err
+	}
+	return n, err
+}
+
+func (b *decompressedBody) Close() error {
+	b.ReadCloser.Close()
+	return b.original.Close()
+}
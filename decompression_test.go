@@ -0,0 +1,87 @@
+package skeleton
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressZipBomb(t *testing.T) {
+	tests := []struct {
+		name string
+		opts DecompressionOptions
+		size int
+	}{
+		// About 10KB of gzip expanding to 1MB, past the default ratio.
+		{"ratio", DecompressionOptions{}, 1 << 20},
+		// Within the ratio, but past MaxBytes.
+		{"max bytes", DecompressionOptions{MaxBytes: 1 << 10, MaxRatio: -1}, 1 << 20},
+	}
+	readers := map[string]func(r *http.Request) error{
+		"read": func(r *http.Request) error {
+			_, err := io.ReadAll(r.Body)
+			return err
+		},
+		"DecodeBody": func(r *http.Request) error {
+			var v interface{}
+			return DecodeBody(r, &v)
+		},
+		"Bind": func(r *http.Request) error {
+			var v struct{}
+			return Bind(&gorouter.RouteContext{R: r}, &v)
+		},
+	}
+	for _, tt := range tests {
+		for readerName, read := range readers {
+			t.Run(tt.name+"/"+readerName, func(t *testing.T) {
+				testDecompressZipBomb(t, tt.opts, tt.size, read)
+			})
+		}
+	}
+}
+
+func testDecompressZipBomb(t *testing.T, opts DecompressionOptions, size int, read func(r *http.Request) error) {
+	body := gzipBytes(t, make([]byte, size))
+	if len(body) > 16<<10 {
+		t.Fatalf("compressed body is %d bytes, want a small body", len(body))
+	}
+
+	var readErr error
+	handler := Decompress(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readErr = read(r)
+		if readErr != nil {
+			w.WriteHeader(ErrorStatus(readErr))
+		}
+	}))
+
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	r.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if !errors.Is(readErr, ErrBodyTooLarge) {
+		t.Errorf("read error = %v, want ErrBodyTooLarge", readErr)
+	}
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/cyc-ttn/gorouter"
@@ -67,5 +68,7 @@ func main() {
 	// clean-up the local cache. It takes in a channel which is closed when
 	// the goroutine should end. The channel will be closed, for example, when
 	// a signal to interrupt the current HTTP server is detected.
-	skeleton.Run(s, nil)
+	if err := skeleton.Run(s, nil); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/cyc-ttn/gorouter"
+
+	"github.com/cyc-ttn/skeleton"
+)
+
+// This example serves a WebSocket echo endpoint through the router. The
+// ResponseWriter provided to handlers supports http.Hijacker (and
+// http.ResponseController), so WebSocket libraries such as
+// github.com/gorilla/websocket can upgrade the connection from a handler:
+//
+// ```
+//
+//	conn, err := upgrader.Upgrade(ctx.W, ctx.R, nil)
+//
+// ```
+//
+// To keep the example free of dependencies, the handshake and a minimal
+// subset of the protocol (unfragmented text and binary frames) are
+// implemented by hand.
+//
+// Once the connection is upgraded, no further HTTP response can be written,
+// so anything which writes headers (e.g., Session.Save, which sets a
+// cookie) must happen before the upgrade.
+func main() {
+	router := skeleton.GoRouter[*gorouter.RouteContext]()
+
+	router.AddRoute(skeleton.GoRoute[*gorouter.RouteContext](
+		http.MethodGet,
+		"/echo",
+		echo,
+	))
+
+	s := skeleton.NewHttpServer[*gorouter.RouteContext, *skeleton.GoRouterRoute[*gorouter.RouteContext]](
+		":80",
+		nil,
+		router,
+		&skeleton.GoHttpServerDelegate{},
+	)
+	if err := skeleton.Run(s, nil); err != nil {
+		log.Fatal(err)
+	}
+}
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+func echo(ctx *gorouter.RouteContext) {
+	if !strings.EqualFold(ctx.R.Header.Get("Upgrade"), "websocket") {
+		ctx.W.WriteHeader(http.StatusUpgradeRequired)
+		return
+	}
+	key := ctx.R.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		ctx.W.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Save the session (if any) here, before the connection is hijacked.
+
+	conn, rw, err := http.NewResponseController(ctx.W).Hijack()
+	if err != nil {
+		ctx.W.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	for {
+		opcode, payload, err := readFrame(rw.Reader)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case 0x1, 0x2: // Text and binary frames are echoed.
+			if writeFrame(rw.Writer, opcode, payload) != nil {
+				return
+			}
+		case 0x8: // Close.
+			writeFrame(rw.Writer, 0x8, nil)
+			return
+		case 0x9: // Ping.
+			if writeFrame(rw.Writer, 0xA, payload) != nil {
+				return
+			}
+		}
+	}
+}
+
+// readFrame reads a single masked frame sent by the client.
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > 1<<20 {
+		return 0, nil, io.ErrShortBuffer
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+// writeFrame writes a single unmasked frame, as sent by servers.
+func writeFrame(w *bufio.Writer, opcode byte, payload []byte) error {
+	w.WriteByte(0x80 | opcode)
+	switch n := len(payload); {
+	case n < 126:
+		w.WriteByte(byte(n))
+	case n <= 0xFFFF:
+		w.WriteByte(126)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	default:
+		w.WriteByte(127)
+		binary.Write(w, binary.BigEndian, uint64(n))
+	}
+	w.Write(payload)
+	return w.Flush()
+}
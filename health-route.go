@@ -0,0 +1,72 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// DefaultReadinessTimeout bounds the time taken by the checks of a readiness
+// request.
+const DefaultReadinessTimeout = 5 * time.Second
+
+// healthResponse is the body of the health and readiness responses.
+type healthResponse struct {
+	Status string   `json:"status"`
+	Failed []string `json:"failed,omitempty"`
+}
+
+// LivenessHandler returns an http.Handler which always responds with a 200,
+// indicating that the process is able to serve requests.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		_ = WriteJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+	})
+}
+
+// ReadinessHandler returns an http.Handler which runs the checks (see
+// RunHealthChecks) and responds with a 200 if they all pass. Otherwise, it
+// responds with a 503 listing the names of the failing checks. The checks
+// are bounded by DefaultReadinessTimeout.
+func ReadinessHandler(checks ...HealthCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		ctx, cancel := context.WithTimeout(r.Context(), DefaultReadinessTimeout)
+		defer cancel()
+
+		err := RunHealthChecks(ctx, checks...)
+		if err == nil {
+			_ = WriteJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+			return
+		}
+		res := healthResponse{Status: "unavailable"}
+		var hErr *HealthCheckError
+		if errors.As(err, &hErr) {
+			res.Failed = hErr.Failed
+		}
+		_ = WriteJSON(w, http.StatusServiceUnavailable, res)
+	})
+}
+
+// AddHealthRoutes adds GET routes for liveness (at healthPath, e.g.,
+// /healthz) and readiness (at readyPath, e.g., /readyz) to the router. See
+// LivenessHandler and ReadinessHandler. The adapter is used to write the
+// responses from the context of the route.
+//
+// ```
+//
+//	err := skeleton.AddHealthRoutes(router, "/healthz", "/readyz", skeleton.GoRouteContextAdapter,
+//	    skeleton.SessionStoreHealthCheck(store),
+//	    skeleton.HealthCheck{Name: "db", Check: db.PingContext},
+//	)
+//
+// ```
+func AddHealthRoutes[Ctx any, R Route[Ctx]](router Router[Ctx, R], healthPath, readyPath string, adapter ContextAdapter[Ctx], checks ...HealthCheck) error {
+	if err := router.AddRoute(GoHandlerRoute(http.MethodGet, healthPath, LivenessHandler(), adapter)); err != nil {
+		return err
+	}
+	return router.AddRoute(GoHandlerRoute(http.MethodGet, readyPath, ReadinessHandler(checks...), adapter))
+}
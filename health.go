@@ -0,0 +1,69 @@
+package skeleton
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// HealthCheck is a named check which determines whether a dependency of the
+// server (e.g., the database or a cache) is healthy. Check should return nil
+// if the dependency is reachable, and respect the cancellation of the
+// provided context.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthCheckError is returned by RunHealthChecks when one or more checks
+// fail. Failed holds the names of the failing checks in the order they were
+// provided.
+type HealthCheckError struct {
+	Failed []string
+	errs   []error
+}
+
+func (e *HealthCheckError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for i, name := range e.Failed {
+		msgs = append(msgs, name+": "+e.errs[i].Error())
+	}
+	return "health checks failed: " + strings.Join(msgs, ", ")
+}
+
+// Unwrap returns the errors returned by the failing checks.
+func (e *HealthCheckError) Unwrap() []error {
+	return e.errs
+}
+
+// RunHealthChecks runs the provided checks in parallel and waits for them to
+// complete. If any check fails, a *HealthCheckError is returned.
+func RunHealthChecks(ctx context.Context, checks ...HealthCheck) error {
+	errs := make([]error, len(checks))
+
+	wg := sync.WaitGroup{}
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c HealthCheck) {
+			defer wg.Done()
+			errs[i] = c.Check(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	var hErr *HealthCheckError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if hErr == nil {
+			hErr = &HealthCheckError{}
+		}
+		hErr.Failed = append(hErr.Failed, checks[i].Name)
+		hErr.errs = append(hErr.errs, err)
+	}
+	if hErr == nil {
+		return nil
+	}
+	return hErr
+}
@@ -0,0 +1,170 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// StatusCoder is implemented by errors which know which HTTP status code
+// should be returned to the client.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// HttpError is an error which carries the HTTP status code that should be
+// returned to the client. Use HttpError.Unwrap to view the underlying error.
+type HttpError struct {
+	status int
+	msg    string
+	err    error
+}
+
+func (e *HttpError) Error() string {
+	return e.msg
+}
+
+func (e *HttpError) Unwrap() error {
+	return e.err
+}
+
+// StatusCode returns the HTTP status code related to the error.
+func (e *HttpError) StatusCode() int {
+	return e.status
+}
+
+func NewHttpError(status int, msg string, err error) *HttpError {
+	return &HttpError{status: status, msg: msg, err: err}
+}
+
+// RouteNotFoundError is returned when no route matches the request. It
+// carries the method and path which were attempted, and wraps the error
+// returned by the Router (if any). For compatibility,
+// errors.Is(err, ErrNoRoute) is true for a RouteNotFoundError.
+type RouteNotFoundError struct {
+	Method string
+	Path   string
+	err    error
+}
+
+func (e *RouteNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s %s", ErrNoRoute.Error(), e.Method, e.Path)
+}
+
+func (e *RouteNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// Is allows errors.Is(err, ErrNoRoute) to match a RouteNotFoundError.
+func (e *RouteNotFoundError) Is(target error) bool {
+	return target == ErrNoRoute
+}
+
+func NewRouteNotFoundError(method, path string, err error) *RouteNotFoundError {
+	return &RouteNotFoundError{Method: method, Path: path, err: err}
+}
+
+// MethodNotAllowedError is returned when a route matches the path of the
+// request, but not its method. Allowed lists the methods of the routes which
+// match the path. It maps to a 405 through ErrorStatus, and the server sets
+// the Allow header accordingly.
+type MethodNotAllowedError struct {
+	Method  string
+	Path    string
+	Allowed []string
+}
+
+func (e *MethodNotAllowedError) Error() string {
+	return fmt.Sprintf("method not allowed: %s %s (allowed: %s)", e.Method, e.Path, strings.Join(e.Allowed, ", "))
+}
+
+// StatusCode returns http.StatusMethodNotAllowed. It implements StatusCoder.
+func (e *MethodNotAllowedError) StatusCode() int {
+	return http.StatusMethodNotAllowed
+}
+
+func NewMethodNotAllowedError(method, path string, allowed []string) *MethodNotAllowedError {
+	return &MethodNotAllowedError{Method: method, Path: path, Allowed: allowed}
+}
+
+// IsTimeout returns true if err (or any error it wraps) is a timeout, such as
+// context.DeadlineExceeded or a net.Error reporting a timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// ErrorStatus classifies an error into the HTTP status code which should be
+// returned to the client. Errors implementing StatusCoder (anywhere in the
+// chain) provide their own status. ErrNoRoute results in a 404. Timeouts (see
+// IsTimeout) result in a 504. Any other error results in a 500.
+//
+// Handlers which respect the deadline of their context can therefore report
+// it consistently:
+//
+// ```
+//
+//	rows, err := db.QueryContext(ctx, query)
+//	if err != nil {
+//	    w.WriteHeader(skeleton.ErrorStatus(err)) // 504 if the deadline passed
+//	    return
+//	}
+//
+// ```
+//
+// A 504 indicates that the work the handler depended on did not complete in
+// time. This differs from timeouts enforced around the handler (e.g., by
+// http.TimeoutHandler), which abandon the handler and respond with a 503.
+func ErrorStatus(err error) int {
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		return sc.StatusCode()
+	}
+	if errors.Is(err, ErrNoRoute) {
+		return http.StatusNotFound
+	}
+	if IsTimeout(err) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// jsonError is the body written by JSONErrorHandler.
+type jsonError struct {
+	Error  string          `json:"error"`
+	Fields ValidationErrors `json:"fields,omitempty"`
+}
+
+// JSONErrorHandler renders errors returned by Serve as JSON, such as
+// {"error":"not_found"}. The status is determined by ErrorStatus, and the
+// error code is derived from it (e.g., method_not_allowed); a 500 results in
+// "internal". The fields of a ValidationErrors are included, as they are
+// meant for the client. Other details of the error are not exposed.
+func JSONErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	status := ErrorStatus(err)
+	body := jsonError{Error: errorCode(status)}
+
+	var vErr ValidationErrors
+	if errors.As(err, &vErr) {
+		body.Fields = vErr
+	}
+	_ = WriteJSON(w, status, body)
+}
+
+// errorCode returns the snake_case error code for the status.
+func errorCode(status int) string {
+	if status == http.StatusInternalServerError {
+		return "internal"
+	}
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
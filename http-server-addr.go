@@ -0,0 +1,75 @@
+package skeleton
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// NormalizeAddr validates and normalizes the address a server listens on.
+// The accepted forms are:
+//
+//   - "" which listens on all interfaces on port 80 (as net/http does);
+//   - a bare port, e.g., "8080" or ":8080";
+//   - a host and port, e.g., "localhost:8080" or "127.0.0.1:8080";
+//   - an IPv6 literal in brackets with a port, e.g., "[::1]:8080".
+//
+// Ports may also be service names (e.g., ":http"). An IPv6 literal without
+// brackets (e.g., "::1") is rejected, as it is ambiguous with a port.
+func NormalizeAddr(addr string) (string, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return ":http", nil
+	}
+
+	// A bare port.
+	if _, err := strconv.Atoi(addr); err == nil {
+		addr = ":" + addr
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		if ip := net.ParseIP(strings.Trim(addr, "[]")); ip != nil {
+			if ip.To4() == nil {
+				return "", fmt.Errorf("invalid address %q: missing port (use [%s]:port for IPv6)", addr, ip)
+			}
+			return "", fmt.Errorf("invalid address %q: missing port", addr)
+		}
+		return "", fmt.Errorf("invalid address %q. %s", addr, err)
+	}
+	if port == "" {
+		return "", fmt.Errorf("invalid address %q: missing port", addr)
+	}
+	if n, err := strconv.Atoi(port); err == nil {
+		if n < 0 || n > 65535 {
+			return "", fmt.Errorf("invalid address %q: port out of range", addr)
+		}
+	} else if _, err := net.LookupPort("tcp", port); err != nil {
+		return "", fmt.Errorf("invalid address %q: unknown port %q", addr, port)
+	}
+	if strings.ContainsAny(host, "/ ") {
+		return "", fmt.Errorf("invalid address %q: invalid host", addr)
+	}
+
+	// Normalize IPv6 literals, which JoinHostPort places in brackets.
+	return net.JoinHostPort(host, port), nil
+}
+
+// newBaseServer creates the underlying http.Server for the provided address
+// and handler, registering the shutdown functions.
+func newBaseServer(addr string, handler http.Handler, onShutdown ...func()) (*http.Server, error) {
+	addr, err := NormalizeAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	server := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+	}
+	for _, f := range onShutdown {
+		server.RegisterOnShutdown(f)
+	}
+	return server, nil
+}
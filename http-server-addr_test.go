@@ -0,0 +1,46 @@
+package skeleton
+
+import "testing"
+
+func TestNormalizeAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"", ":http"},
+		{"8080", ":8080"},
+		{":8080", ":8080"},
+		{" 8080 ", ":8080"},
+		{"localhost:8080", "localhost:8080"},
+		{"127.0.0.1:8080", "127.0.0.1:8080"},
+		{"[::1]:8080", "[::1]:8080"},
+		{"[::]:443", "[::]:443"},
+		{"[fe80::1%eth0]:8080", "[fe80::1%eth0]:8080"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeAddr(tt.addr)
+		if err != nil {
+			t.Errorf("NormalizeAddr(%q) returned %v", tt.addr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeAddr(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAddrInvalid(t *testing.T) {
+	for _, addr := range []string{
+		"::1",
+		"[::1]",
+		"127.0.0.1",
+		"localhost:",
+		":65536",
+		"70000",
+		"bad host:80",
+	} {
+		if got, err := NormalizeAddr(addr); err == nil {
+			t.Errorf("NormalizeAddr(%q) = %q, want an error", addr, got)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// AuthSessionKey is the session key checked to determine whether a request
+// is authenticated when HttpServer.Authenticate is not provided.
+const AuthSessionKey = "user"
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+)
+
+// authenticate checks whether the request is authenticated. Errors which do
+// not carry a status are wrapped in a 401 *HttpError.
+func (s *HttpServer[Ctx, R]) authenticate(r *http.Request, sess Session) error {
+	var err error
+	switch {
+	case s.Authenticate != nil:
+		err = s.Authenticate(r, sess)
+	case sess == nil || sess.GetValue(AuthSessionKey) == nil:
+		err = ErrUnauthenticated
+	}
+	if err == nil {
+		return nil
+	}
+
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		return err
+	}
+	return NewHttpError(http.StatusUnauthorized, ErrUnauthenticated.Error(), err)
+}
+
+// BearerToken returns the token of an Authorization header using the Bearer
+// scheme, or an empty string if there is none. It is intended for use within
+// HttpServer.Authenticate.
+func BearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
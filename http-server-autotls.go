@@ -0,0 +1,107 @@
+package skeleton
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"sync"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// DefaultAutoTLSCacheDir is the directory in which NewAutoTLSServer caches
+// certificates.
+const DefaultAutoTLSCacheDir = "autocert-cache"
+
+// AutoTLSServer serves a handler over HTTPS with certificates obtained
+// automatically through ACME (e.g., from Let's Encrypt) by an
+// autocert.Manager. It also listens on HTTP, to answer the HTTP-01 challenge
+// and redirect other requests to HTTPS.
+//
+// The ACME server needs to reach the server on port 80 (for the challenge)
+// and clients on port 443, so HTTPAddr and HTTPSAddr should only be changed
+// if those ports are forwarded. The certificate cache of the manager must
+// persist across restarts, as certificate authorities rate limit issuance.
+//
+// ```
+//
+//	server := skeleton.NewAutoTLSServer("example.com", "www.example.com")
+//	server.Manager.Cache = autocert.DirCache("/var/lib/app/certs")
+//	server.Handler = httpServer
+//	log.Fatal(server.Run())
+//
+// ```
+type AutoTLSServer struct {
+	Manager *autocert.Manager
+	Handler http.Handler
+
+	HTTPAddr  string // Defaults to ":80".
+	HTTPSAddr string // Defaults to ":443".
+
+	mu          sync.Mutex
+	httpServer  *http.Server
+	httpsServer *http.Server
+}
+
+// NewAutoTLSServer creates an AutoTLSServer which obtains certificates for
+// the provided domains only, accepting the terms of service of the
+// certificate authority, and caching them in DefaultAutoTLSCacheDir. The
+// Handler needs to be set before running the server.
+func NewAutoTLSServer(domains ...string) *AutoTLSServer {
+	return &AutoTLSServer{
+		Manager: &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(domains...),
+			Cache:      autocert.DirCache(DefaultAutoTLSCacheDir),
+		},
+		HTTPAddr:  ":80",
+		HTTPSAddr: ":443",
+	}
+}
+
+// Run both servers. This is a blocking function, which returns once either
+// server stops.
+func (s *AutoTLSServer) Run(onShutdown ...func()) error {
+	httpServer, err := newBaseServer(s.HTTPAddr, s.Manager.HTTPHandler(nil))
+	if err != nil {
+		return err
+	}
+	httpsServer, err := newBaseServer(s.HTTPSAddr, s.Handler, onShutdown...)
+	if err != nil {
+		return err
+	}
+	// The configuration of the manager also answers the TLS-ALPN-01
+	// challenge.
+	httpsServer.TLSConfig = s.Manager.TLSConfig()
+	httpsServer.TLSConfig.MinVersion = tls.VersionTLS12
+
+	s.mu.Lock()
+	s.httpServer = httpServer
+	s.httpsServer = httpsServer
+	s.mu.Unlock()
+
+	errs := make(chan error, 2)
+	go func() { errs <- httpServer.ListenAndServe() }()
+	go func() { errs <- httpsServer.ListenAndServeTLS("", "") }()
+
+	err = <-errs
+	if !errors.Is(err, http.ErrServerClosed) {
+		// Stop the other server, so that Run does not leave it behind.
+		httpServer.Close()
+		httpsServer.Close()
+	}
+	return err
+}
+
+// Shutdown both servers.
+func (s *AutoTLSServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	httpServer, httpsServer := s.httpServer, s.httpsServer
+	s.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+	return errors.Join(httpServer.Shutdown(ctx), httpsServer.Shutdown(ctx))
+}
@@ -0,0 +1,134 @@
+package skeleton
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrCoalescedRequestFailed = errors.New("coalesced request failed")
+)
+
+// coalescedResponse is a response which was recorded once and is shared by
+// all coalesced requests. It must not be modified once recorded.
+type coalescedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// writeTo writes a copy of the response to w. Set-Cookie is only written for
+// the request which produced the response, so that per-user state is never
+// shared with other clients.
+func (c *coalescedResponse) writeTo(w http.ResponseWriter, leader bool) {
+	h := w.Header()
+	for k, v := range c.header {
+		if !leader && k == "Set-Cookie" {
+			continue
+		}
+		h[k] = append([]string(nil), v...)
+	}
+	w.WriteHeader(c.status)
+	_, _ = w.Write(c.body)
+}
+
+// responseRecorder buffers a response so that it can be shared between
+// coalesced requests. Streaming (e.g., Flush) and Hijack are not supported
+// on coalesced routes.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// written returns true if a status or body was written.
+func (r *responseRecorder) written() bool {
+	return r.status != 0
+}
+
+// result returns the immutable recorded response, or nil if nothing was
+// written.
+func (r *responseRecorder) result() *coalescedResponse {
+	if !r.written() {
+		return nil
+	}
+	return &coalescedResponse{
+		status: r.status,
+		header: r.header.Clone(),
+		body:   r.body.Bytes(),
+	}
+}
+
+// coalescer shares the response of identical concurrent requests, so that
+// the handler only runs once for all of them.
+type coalescer struct {
+	g singleflight.Group
+}
+
+// serve runs handler for the first request with the provided key, while
+// identical concurrent requests wait for and receive a copy of its response.
+//
+// If the handler returns an error, the recorded response is discarded and
+// the error is returned for every request. If the handler writes nothing,
+// nothing is written for any request, so that each handles the empty
+// response as if it were not coalesced.
+//
+// If the handler panics, the panic is recovered so that it does not spread to
+// the waiting requests, and re-raised for the first request only; the
+// waiting requests receive ErrCoalescedRequestFailed as a 500 *HttpError.
+func (c *coalescer) serve(w http.ResponseWriter, key string, handler func(rec *responseRecorder) error) error {
+	leader := false
+	var panicked bool
+	var panicValue interface{}
+	v, err, _ := c.g.Do(key, func() (v interface{}, err error) {
+		leader = true
+		defer func() {
+			if p := recover(); p != nil {
+				panicked, panicValue = true, p
+				v = nil
+				err = NewHttpError(http.StatusInternalServerError, ErrCoalescedRequestFailed.Error(), ErrCoalescedRequestFailed)
+			}
+		}()
+		rec := &responseRecorder{header: http.Header{}}
+		if err := handler(rec); err != nil {
+			return nil, err
+		}
+		return rec.result(), nil
+	})
+	if leader && panicked {
+		panic(panicValue)
+	}
+	if err != nil {
+		return err
+	}
+	if res, _ := v.(*coalescedResponse); res != nil {
+		res.writeTo(w, leader)
+	}
+	return nil
+}
+
+// coalesceKey returns the default key used to coalesce requests, which
+// identifies the route and its parameters.
+func coalesceKey(r *http.Request) string {
+	return r.Method + " " + r.URL.RequestURI()
+}
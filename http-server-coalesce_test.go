@@ -0,0 +1,97 @@
+package skeleton
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+// coalescedErrorRoute is a route whose handler returns an error, and whose
+// requests are coalesced.
+type coalescedErrorRoute struct {
+	*GoErrorRoute[*gorouter.RouteContext]
+	meta RouteMetadata
+}
+
+func (r *coalescedErrorRoute) Metadata() RouteMetadata {
+	return r.meta
+}
+
+func newCoalesceServer(fn func(ctx *gorouter.RouteContext) error, meta RouteMetadata) *HttpServer[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]] {
+	meta.Coalesce = true
+	router := GoRouter[*gorouter.RouteContext]()
+	router.AddRoute(&coalescedErrorRoute{
+		GoErrorRoute: GoRouteE("GET", "/items", fn).(*GoErrorRoute[*gorouter.RouteContext]),
+		meta:         meta,
+	})
+	return NewHttpServer[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]](
+		"", nil, router, &GoHttpServerDelegate{})
+}
+
+func TestCoalesceHandlerError(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	server := newCoalesceServer(func(ctx *gorouter.RouteContext) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return NewHttpError(http.StatusNotFound, "item not found", nil)
+	}, RouteMetadata{})
+
+	statuses := make([]int, 2)
+	bodies := make([]string, 2)
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			statuses[i], _ = server.ServeAndRender(w, httptest.NewRequest("GET", "/items", nil))
+			bodies[i] = w.Body.String()
+		}(i)
+		// Let the first request become the leader before the second joins.
+		time.Sleep(20 * time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("handler called %d times, want 1", n)
+	}
+	for i, status := range statuses {
+		if status != http.StatusNotFound {
+			t.Errorf("request %d: status = %d, want %d (body %q)", i, status, http.StatusNotFound, bodies[i])
+		}
+	}
+}
+
+func TestCoalesceTimeout(t *testing.T) {
+	server := newCoalesceServer(func(ctx *gorouter.RouteContext) error {
+		<-ctx.R.Context().Done()
+		return nil
+	}, RouteMetadata{Timeout: 20 * time.Millisecond})
+
+	status, err := server.ServeAndRender(httptest.NewRecorder(), httptest.NewRequest("GET", "/items", nil))
+	if status != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", status, http.StatusGatewayTimeout)
+	}
+	if ErrorStatus(err) != http.StatusGatewayTimeout {
+		t.Errorf("error = %v, want a 504", err)
+	}
+}
+
+func TestCoalesceShareResponse(t *testing.T) {
+	server := newCoalesceServer(func(ctx *gorouter.RouteContext) error {
+		return WriteJSON(ctx.W, http.StatusOK, "ok")
+	}, RouteMetadata{})
+
+	w := httptest.NewRecorder()
+	status, err := server.ServeAndRender(w, httptest.NewRequest("GET", "/items", nil))
+	if err != nil || status != http.StatusOK || w.Body.String() == "" {
+		t.Errorf("got %d %q (%v), want 200 with a body", status, w.Body.String(), err)
+	}
+}
@@ -5,7 +5,8 @@ import "net/http"
 type HttpServerDelegate[Ctx any, R Route[Ctx]] interface {
 	// Generate should generate a context to pass into the routes. The route
 	// and related session is provided. This is used only in the Serve and
-	// ServeHTTP functions.
+	// ServeHTTP functions. The session is nil if the server was created
+	// without a SessionStore.
 	Generate(http.ResponseWriter, *http.Request, R, Session) Ctx
 }
 
@@ -0,0 +1,62 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDraining = errors.New("server is draining")
+)
+
+// Drainer is implemented by servers which report whether they are shutting
+// down, such as HttpServer and LoggingHttpServer.
+type Drainer interface {
+	Draining() bool
+}
+
+// Draining returns true once the server has started shutting down. In-flight
+// requests (and requests on existing connections) are still served until
+// the shutdown completes.
+func (s *HttpServer[Ctx, R]) Draining() bool {
+	return s.draining.Load()
+}
+
+// drain marks the server as draining, and waits for DrainDelay, unless ctx
+// is done first.
+func (s *HttpServer[Ctx, R]) drain(ctx context.Context) {
+	if s.draining.Swap(true) || s.DrainDelay <= 0 {
+		return
+	}
+	t := time.NewTimer(s.DrainDelay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// DrainingHealthCheck returns a HealthCheck which fails with ErrDraining once
+// the server has started shutting down. Used as a readiness check, the load
+// balancer stops sending traffic to the server while in-flight requests
+// complete; set HttpServer.DrainDelay to give it time to notice.
+//
+// ```
+//
+//	err := skeleton.AddHealthRoutes(router, "/healthz", "/readyz", skeleton.GoRouteContextAdapter,
+//	    skeleton.DrainingHealthCheck(server),
+//	)
+//
+// ```
+func DrainingHealthCheck(d Drainer) HealthCheck {
+	return HealthCheck{
+		Name: "draining",
+		Check: func(ctx context.Context) error {
+			if d.Draining() {
+				return ErrDraining
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrAmbiguousFraming = errors.New("ambiguous request framing")
+	ErrMalformedRequest = errors.New("malformed request")
+)
+
+// checkFraming rejects requests whose message framing is ambiguous or
+// invalid, which is a common vector for request smuggling when the server
+// sits behind a proxy that interprets the framing differently.
+//
+// net/http's server already rejects requests with multiple differing
+// Content-Length values, with an invalid Content-Length, and with a
+// Transfer-Encoding other than chunked. It collapses identical Content-Length
+// values, and drops Content-Length when Transfer-Encoding is chunked, instead
+// of rejecting the request. This check adds explicit rejection of:
+//   - requests that present both Transfer-Encoding and Content-Length;
+//   - requests with more than one Content-Length header, even if equal;
+//   - Content-Length or Transfer-Encoding values that slipped through other
+//     front-ends (e.g., when ServeHTTP is invoked by something other than
+//     net/http's server).
+func checkFraming(r *http.Request) error {
+	contentLengths := r.Header.Values("Content-Length")
+	transferEncoding := len(r.TransferEncoding) > 0 || len(r.Header.Values("Transfer-Encoding")) > 0
+
+	switch {
+	case transferEncoding && len(contentLengths) > 0:
+		return framingError("both Content-Length and Transfer-Encoding are present")
+	case len(contentLengths) > 1:
+		return framingError("multiple Content-Length headers")
+	case len(contentLengths) == 1:
+		if n, err := strconv.ParseInt(contentLengths[0], 10, 64); err != nil || n < 0 {
+			return framingError("invalid Content-Length")
+		}
+	}
+
+	if len(r.TransferEncoding) > 1 || (len(r.TransferEncoding) == 1 && r.TransferEncoding[0] != "chunked") {
+		return framingError("unsupported Transfer-Encoding")
+	}
+	return nil
+}
+
+func framingError(msg string) error {
+	return NewHttpError(http.StatusBadRequest, msg, ErrAmbiguousFraming)
+}
+
+// checkRequestLine rejects requests with an empty method or a path which is
+// not absolute, as they cannot be matched meaningfully. The asterisk form
+// used by OPTIONS * is allowed.
+func checkRequestLine(r *http.Request) error {
+	if r.Method == "" {
+		return NewHttpError(http.StatusBadRequest, "empty request method", ErrMalformedRequest)
+	}
+	if r.Method == http.MethodOptions && r.URL.Path == "*" {
+		return nil
+	}
+	if !strings.HasPrefix(r.URL.Path, "/") {
+		return NewHttpError(http.StatusBadRequest, "request path is not absolute", ErrMalformedRequest)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+func newMalformedRequestServer() *HttpServer[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]] {
+	router := GoRouter[*gorouter.RouteContext]()
+	router.AddRoute(GoRoute("GET", "/users", func(ctx *gorouter.RouteContext) {
+		ctx.W.WriteHeader(http.StatusOK)
+	}))
+	return NewHttpServer[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]](
+		"", nil, router, &GoHttpServerDelegate{})
+}
+
+func TestMalformedRequestLine(t *testing.T) {
+	emptyMethod := httptest.NewRequest("GET", "/users", nil)
+	emptyMethod.Method = ""
+
+	relativePath := httptest.NewRequest("GET", "/users", nil)
+	relativePath.URL.Path = "users"
+
+	for name, r := range map[string]*http.Request{
+		"empty method":  emptyMethod,
+		"relative path": relativePath,
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := newMalformedRequestServer()
+			status, err := server.ServeAndRender(httptest.NewRecorder(), r)
+			if !errors.Is(err, ErrMalformedRequest) {
+				t.Errorf("error = %v, want ErrMalformedRequest", err)
+			}
+			if status != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", status, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestMalformedRequestLineAllowed(t *testing.T) {
+	server := newMalformedRequestServer()
+	server.AllowMalformedRequests = true
+
+	r := httptest.NewRequest("GET", "/users", nil)
+	r.URL.Path = "users"
+	if err := server.Serve(httptest.NewRecorder(), r); errors.Is(err, ErrMalformedRequest) {
+		t.Errorf("error = %v, want the request to reach the router", err)
+	}
+}
+
+func TestAmbiguousFraming(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		contentLength    []string
+		transferEncoding []string
+		wantErr          bool
+	}{
+		{name: "content length", contentLength: []string{"5"}},
+		{name: "chunked", transferEncoding: []string{"chunked"}},
+		{name: "content length and transfer encoding", contentLength: []string{"5"}, transferEncoding: []string{"chunked"}, wantErr: true},
+		{name: "duplicate content length", contentLength: []string{"5", "5"}, wantErr: true},
+		{name: "invalid content length", contentLength: []string{"five"}, wantErr: true},
+		{name: "negative content length", contentLength: []string{"-1"}, wantErr: true},
+		{name: "gzip transfer encoding", transferEncoding: []string{"gzip"}, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/users", nil)
+			for _, v := range tt.contentLength {
+				r.Header.Add("Content-Length", v)
+			}
+			for _, v := range tt.transferEncoding {
+				r.Header.Add("Transfer-Encoding", v)
+			}
+			r.TransferEncoding = tt.transferEncoding
+
+			server := newMalformedRequestServer()
+			status, err := server.ServeAndRender(httptest.NewRecorder(), r)
+			if got := errors.Is(err, ErrAmbiguousFraming); got != tt.wantErr {
+				t.Fatalf("error = %v, want ErrAmbiguousFraming: %v", err, tt.wantErr)
+			}
+			if tt.wantErr && status != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", status, http.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package skeleton
+
+import (
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// applyH2C wraps the handler of the server so that it serves HTTP/2 over
+// cleartext, if EnableH2C is set. HTTP/1 requests are passed through to the
+// handler unchanged.
+func (s *HttpServer[Ctx, R]) applyH2C(server *http.Server) {
+	if !s.EnableH2C || s.TLSConfig != nil {
+		return
+	}
+	server.Handler = h2c.NewHandler(server.Handler, &http2.Server{
+		IdleTimeout: server.IdleTimeout,
+	})
+}
@@ -0,0 +1,57 @@
+package skeleton
+
+import (
+	"context"
+	"net/http"
+)
+
+// ContextKey is the type of the keys under which request headers are stored
+// in the request context by HttpServer.HeaderExtractors.
+type ContextKey string
+
+// HeaderValue returns the value stored in the context under the provided key
+// by HttpServer.HeaderExtractors, or an empty string if there is none.
+func HeaderValue(ctx context.Context, key ContextKey) string {
+	v, _ := ctx.Value(key).(string)
+	return v
+}
+
+// extractHeaders copies the headers listed in extractors into the context of
+// the request. Only the first value of a header is used. Missing or empty
+// headers are skipped, leaving no value in the context. A value already
+// present in the context (e.g., set by a trusted middleware) takes precedence
+// over the header, which is supplied by the client.
+func extractHeaders(r *http.Request, extractors map[string]ContextKey) *http.Request {
+	ctx := r.Context()
+	for header, key := range extractors {
+		v := r.Header.Get(header)
+		if v == "" || ctx.Value(key) != nil {
+			continue
+		}
+		ctx = context.WithValue(ctx, key, v)
+	}
+	if ctx == r.Context() {
+		return r
+	}
+	return r.WithContext(ctx)
+}
+
+// headerLogFields adds the values extracted by extractors to the log fields,
+// keyed by their ContextKey. The value in the context of the request is
+// logged when it took precedence over the header. Keys which are already
+// present in fields (e.g., ID, Method and Path) are skipped, so that a header
+// cannot overwrite the fields set by the server.
+func headerLogFields(r *http.Request, extractors map[string]ContextKey, fields map[string]interface{}) {
+	for header, key := range extractors {
+		if _, reserved := fields[string(key)]; reserved {
+			continue
+		}
+		v := HeaderValue(r.Context(), key)
+		if v == "" {
+			v = r.Header.Get(header)
+		}
+		if v != "" {
+			fields[string(key)] = v
+		}
+	}
+}
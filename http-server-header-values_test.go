@@ -0,0 +1,34 @@
+package skeleton
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderLogFields(t *testing.T) {
+	extractors := map[string]ContextKey{
+		"X-Tenant-ID": "tenant",
+		"X-User-ID":   "user",
+		"X-Path":      "Path",
+	}
+	r := httptest.NewRequest("GET", "/users", nil)
+	r.Header.Set("X-Tenant-ID", "spoofed")
+	r.Header.Set("X-User-ID", "42")
+	r.Header.Set("X-Path", "/admin")
+	r = r.WithContext(context.WithValue(r.Context(), ContextKey("tenant"), "trusted"))
+	r = extractHeaders(r, extractors)
+
+	fields := map[string]interface{}{"Path": r.URL.Path}
+	headerLogFields(r, extractors, fields)
+
+	if got := fields["tenant"]; got != "trusted" {
+		t.Errorf("tenant = %v, want the value of the context", got)
+	}
+	if got := fields["user"]; got != "42" {
+		t.Errorf("user = %v, want 42", got)
+	}
+	if got := fields["Path"]; got != "/users" {
+		t.Errorf("Path = %v, want it to be left unchanged", got)
+	}
+}
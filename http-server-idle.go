@@ -0,0 +1,109 @@
+package skeleton
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConnIdleTimeouts closes idle keep-alive connections after a timeout which
+// depends on whether a valid session has been seen on the connection. This
+// allows connections from scanners and other unauthenticated clients to be
+// reclaimed sooner than those of users.
+//
+// Session validity is tracked per connection: once a request on the
+// connection carries an existing session (or passes Authenticate), the
+// connection is considered authenticated for the rest of its life. As
+// keep-alive connections may be reused by a proxy for several clients, this
+// is a heuristic for resource protection, not a security boundary.
+//
+// The timeouts are in addition to http.Server.IdleTimeout, which closes
+// connections regardless of authentication. It should be left unset, or set
+// to at least the longer of the two timeouts.
+type ConnIdleTimeouts struct {
+	Unauthenticated time.Duration
+	Authenticated   time.Duration
+
+	conns sync.Map // net.Conn -> *connIdleState
+}
+
+// connIdleState is the state of a single connection.
+type connIdleState struct {
+	authenticated atomic.Bool
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+type connIdleStateKey struct{}
+
+// apply installs the ConnContext and ConnState hooks on the server, chaining
+// any existing hooks. It is safe to call on a nil ConnIdleTimeouts.
+func (t *ConnIdleTimeouts) apply(srv *http.Server) {
+	if t == nil {
+		return
+	}
+
+	connContext := srv.ConnContext
+	srv.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		if connContext != nil {
+			ctx = connContext(ctx, c)
+		}
+		state := &connIdleState{}
+		t.conns.Store(c, state)
+		return context.WithValue(ctx, connIdleStateKey{}, state)
+	}
+
+	connState := srv.ConnState
+	srv.ConnState = func(c net.Conn, s http.ConnState) {
+		t.connState(c, s)
+		if connState != nil {
+			connState(c, s)
+		}
+	}
+}
+
+func (t *ConnIdleTimeouts) connState(c net.Conn, s http.ConnState) {
+	v, ok := t.conns.Load(c)
+	if !ok {
+		return
+	}
+	state := v.(*connIdleState)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+
+	switch s {
+	case http.StateIdle:
+		timeout := t.Unauthenticated
+		if state.authenticated.Load() {
+			timeout = t.Authenticated
+		}
+		if timeout > 0 {
+			state.timer = time.AfterFunc(timeout, func() {
+				_ = c.Close()
+			})
+		}
+	case http.StateHijacked, http.StateClosed:
+		t.conns.Delete(c)
+	}
+}
+
+// MarkConnAuthenticated marks the connection serving the request, whose
+// context is provided, as authenticated, so that it receives the
+// Authenticated idle timeout. The HttpServer calls it automatically when an
+// existing session is seen; it can also be called by handlers (e.g., after
+// validating a bearer token). It has no effect if ConnIdleTimeouts is not in
+// use.
+func MarkConnAuthenticated(ctx context.Context) {
+	if state, ok := ctx.Value(connIdleStateKey{}).(*connIdleState); ok {
+		state.authenticated.Store(true)
+	}
+}
@@ -0,0 +1,79 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/monstercat/golib/logger"
+)
+
+// LoggingRunDelegate is a run delegate which logs the lifecycle of the
+// server: when it starts and stops, any error returned while running or
+// shutting down, as well as when each of its routines starts and exits. Use
+// NewLoggingRunDelegate to initialize it.
+type LoggingRunDelegate struct {
+	logger.Logger
+
+	// RunRoutines are the routines run in parallel to the server. See
+	// RunDelegate.Routines.
+	RunRoutines []RunRoutine
+}
+
+// NewLoggingRunDelegate creates a run delegate which logs to l, running the
+// provided routines in parallel to the server.
+func NewLoggingRunDelegate(l logger.Logger, routines ...RunRoutine) *LoggingRunDelegate {
+	return &LoggingRunDelegate{
+		Logger:      l,
+		RunRoutines: routines,
+	}
+}
+
+// Routines returns the routines of the delegate, each logging when it starts
+// and exits. Routines are identified by their index in RunRoutines.
+func (d *LoggingRunDelegate) Routines() []RunRoutine {
+	routines := make([]RunRoutine, len(d.RunRoutines))
+	for i, routine := range d.RunRoutines {
+		i, routine := i, routine
+		routines[i] = func(done <-chan struct{}) {
+			d.Log(logger.SeverityInfo, map[string]interface{}{
+				"Message": "routine starting",
+				"Routine": i,
+			})
+			defer d.Log(logger.SeverityInfo, map[string]interface{}{
+				"Message": "routine exited",
+				"Routine": i,
+			})
+			routine(done)
+		}
+	}
+	return routines
+}
+
+// WrapRun logs that the server is starting, as well as the error it returns,
+// if any. http.ErrServerClosed is expected on shutdown, so it is not logged.
+func (d *LoggingRunDelegate) WrapRun(fn func() error) error {
+	d.Log(logger.SeverityInfo, "server starting")
+	err := fn()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		d.Log(logger.SeverityError, map[string]interface{}{
+			"Message": "server failed",
+			"Error":   err.Error(),
+		})
+	}
+	return err
+}
+
+// WrapShutdown logs that the server is shutting down, the error returned by
+// the shutdown, if any, and when the server has stopped.
+func (d *LoggingRunDelegate) WrapShutdown(fn func() error) error {
+	d.Log(logger.SeverityInfo, "server shutting down")
+	err := fn()
+	if err != nil {
+		d.Log(logger.SeverityError, map[string]interface{}{
+			"Message": "server shutdown failed",
+			"Error":   err.Error(),
+		})
+	}
+	d.Log(logger.SeverityInfo, "server stopped")
+	return err
+}
@@ -0,0 +1,147 @@
+package skeleton
+
+import (
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MaintenanceMode rejects user traffic with a 503 while maintenance is
+// performed, without shutting the server down. Unlike draining, the server
+// keeps accepting connections, so exempt paths (e.g., health checks and
+// admin endpoints) continue to be served.
+//
+// Maintenance can be toggled at runtime through Enable and Disable, through
+// the handler returned by AdminHandler, or through a signal using
+// NotifySignal.
+type MaintenanceMode struct {
+	// Paths restricts maintenance to requests whose path starts with one of
+	// the provided prefixes. If empty, the entire server is in maintenance.
+	Paths []string
+
+	// ExemptPaths are path prefixes which are always served (e.g., /health).
+	ExemptPaths []string
+
+	// RetryAfter is sent in the Retry-After header. Defaults to
+	// DefaultRetryAfter seconds.
+	RetryAfter time.Duration
+
+	// Response, if provided, writes the maintenance response (e.g., an HTML
+	// page). It is responsible for writing the 503 status. By default, a
+	// JSON message is written.
+	Response http.Handler
+
+	enabled atomic.Bool
+}
+
+// Enable puts the server into maintenance.
+func (m *MaintenanceMode) Enable() {
+	m.enabled.Store(true)
+}
+
+// Disable takes the server out of maintenance.
+func (m *MaintenanceMode) Disable() {
+	m.enabled.Store(false)
+}
+
+// Enabled returns true if maintenance is enabled. It is safe to call on a nil
+// MaintenanceMode.
+func (m *MaintenanceMode) Enabled() bool {
+	return m != nil && m.enabled.Load()
+}
+
+// applies returns true if the request should receive the maintenance
+// response.
+func (m *MaintenanceMode) applies(r *http.Request) bool {
+	if !m.Enabled() {
+		return false
+	}
+	path := r.URL.Path
+	for _, p := range m.ExemptPaths {
+		if strings.HasPrefix(path, p) {
+			return false
+		}
+	}
+	if len(m.Paths) == 0 {
+		return true
+	}
+	for _, p := range m.Paths {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// serve writes the maintenance response if it applies to the request. It
+// returns true if the response has been written.
+func (m *MaintenanceMode) serve(w http.ResponseWriter, r *http.Request) bool {
+	if !m.applies(r) {
+		return false
+	}
+
+	retryAfter := DefaultRetryAfter
+	if m.RetryAfter > 0 {
+		retryAfter = ceilSeconds(m.RetryAfter)
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+
+	if m.Response != nil {
+		m.Response.ServeHTTP(w, r)
+		return true
+	}
+	_ = WriteJSON(w, http.StatusServiceUnavailable, map[string]string{
+		"error": "The service is undergoing maintenance. Please try again later.",
+	})
+	return true
+}
+
+// AdminHandler returns a handler to toggle maintenance. PUT and POST enable
+// maintenance, DELETE disables it, and GET reports the current state. The
+// handler should be mounted on an exempt, protected path.
+func (m *MaintenanceMode) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut, http.MethodPost:
+			m.Enable()
+		case http.MethodDelete:
+			m.Disable()
+		case http.MethodGet, http.MethodHead:
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		_ = WriteJSON(w, http.StatusOK, map[string]bool{
+			"maintenance": m.Enabled(),
+		})
+	})
+}
+
+// NotifySignal toggles maintenance each time one of the provided signals
+// (e.g., syscall.SIGUSR1) is received. Call the returned function to stop
+// listening.
+func (m *MaintenanceMode) NotifySignal(sig ...os.Signal) (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, sig...)
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				m.enabled.Store(!m.enabled.Load())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
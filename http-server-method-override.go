@@ -0,0 +1,135 @@
+package skeleton
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// MethodOverrideHeader is the header used to override the method of a
+	// POST request.
+	MethodOverrideHeader = "X-HTTP-Method-Override"
+
+	// MethodOverrideField is the form field used to override the method of a
+	// POST request.
+	MethodOverrideField = "_method"
+
+	// MaxMethodOverrideBody is the maximum size of a URL-encoded form which
+	// is read to find the MethodOverrideField. This matches the limit used by
+	// http.Request.ParseForm.
+	MaxMethodOverrideBody = 10 << 20
+
+	// maxMethodOverridePart is the length beyond which the names and values
+	// of the form are skipped while looking for the MethodOverrideField.
+	maxMethodOverridePart = 64
+)
+
+// methodOverrideTargets are the methods a POST request may be overridden to.
+// Overriding to safe methods (e.g., GET) is not allowed, as it would allow a
+// request with side effects to be treated as one without.
+var methodOverrideTargets = map[string]bool{
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// overrideMethod returns the method which should be used to match the
+// request. Only POST requests can be overridden, either through the
+// MethodOverrideHeader or, for URL-encoded forms, the MethodOverrideField.
+// The header takes precedence. Invalid override values are ignored.
+//
+// Reading the form field requires the body to be buffered. The body is
+// restored, so handlers can still read or parse it. If a buffer is returned,
+// it must be closed once the request has been served. The buffered form is
+// streamed while looking for the field, so that large bodies which spilled
+// to a temporary file are not read into memory.
+func overrideMethod(r *http.Request) (string, *bodyBuffer, error) {
+	if r.Method != http.MethodPost {
+		return r.Method, nil, nil
+	}
+
+	var body *bodyBuffer
+	method := r.Header.Get(MethodOverrideHeader)
+	if method == "" && isURLEncodedForm(r) {
+		var err error
+		body, err = bufferBody(r, MaxMethodOverrideBody)
+		if err != nil {
+			return r.Method, nil, err
+		}
+		method, err = formValue(body.Reader(), MethodOverrideField)
+		if err != nil {
+			return r.Method, body, err
+		}
+	}
+
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if !methodOverrideTargets[method] {
+		return r.Method, body, nil
+	}
+	return method, body, nil
+}
+
+func isURLEncodedForm(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
+}
+
+// formValue returns the first value of the named field of a URL-encoded form,
+// or an empty string if there is none. The form is streamed, and only names
+// and values up to maxMethodOverridePart bytes are held in memory; longer
+// ones cannot match and are skipped. Malformed pairs are skipped, as done by
+// http.Request.ParseForm.
+func formValue(r io.Reader, name string) (string, error) {
+	br := bufio.NewReader(r)
+	for {
+		key, keyOk, delim, err := readFormPart(br, "=&")
+		if err != nil {
+			return "", err
+		}
+		value, valueOk := "", true
+		if delim == '=' {
+			if value, valueOk, delim, err = readFormPart(br, "&"); err != nil {
+				return "", err
+			}
+		}
+		if keyOk && valueOk && !strings.Contains(key, ";") && !strings.Contains(value, ";") {
+			k, kErr := url.QueryUnescape(key)
+			v, vErr := url.QueryUnescape(value)
+			if kErr == nil && vErr == nil && k == name {
+				return v, nil
+			}
+		}
+		if delim == 0 {
+			return "", nil
+		}
+	}
+}
+
+// readFormPart reads up to the first of delims, which is returned, or to the
+// end of the form, in which case delim is 0. ok is false if the part exceeded
+// maxMethodOverridePart bytes, in which case it is truncated.
+func readFormPart(br *bufio.Reader, delims string) (part string, ok bool, delim byte, err error) {
+	var b strings.Builder
+	ok = true
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			return b.String(), ok, 0, nil
+		}
+		if err != nil {
+			return "", false, 0, err
+		}
+		if strings.IndexByte(delims, c) >= 0 {
+			return b.String(), ok, c, nil
+		}
+		if b.Len() < maxMethodOverridePart {
+			b.WriteByte(c)
+		} else {
+			ok = false
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package skeleton
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOverrideMethodHeader(t *testing.T) {
+	tests := []struct {
+		method   string
+		override string
+		want     string
+	}{
+		{"POST", "PUT", "PUT"},
+		{"POST", " delete ", "DELETE"},
+		{"POST", "GET", "POST"},
+		{"POST", "", "POST"},
+		{"GET", "DELETE", "GET"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/", nil)
+		r.Header.Set(MethodOverrideHeader, tt.override)
+		got, body, err := overrideMethod(r)
+		if err != nil {
+			t.Fatalf("%s with %q: %v", tt.method, tt.override, err)
+		}
+		body.Close()
+		if got != tt.want {
+			t.Errorf("%s with %q = %s, want %s", tt.method, tt.override, got, tt.want)
+		}
+	}
+}
+
+func TestOverrideMethodFormField(t *testing.T) {
+	form := "name=test&" + MethodOverrideField + "=patch"
+	r := httptest.NewRequest("POST", "/", strings.NewReader(form))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	got, body, err := overrideMethod(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if got != "PATCH" {
+		t.Errorf("method = %s, want PATCH", got)
+	}
+
+	// The body is restored for the handler.
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != form {
+		t.Errorf("body = %q, want %q", b, form)
+	}
+}
+
+func TestOverrideMethodHeaderPrecedence(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(MethodOverrideField+"=DELETE"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set(MethodOverrideHeader, "PUT")
+
+	got, body, err := overrideMethod(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+	if got != "PUT" {
+		t.Errorf("method = %s, want PUT", got)
+	}
+}
+
+func TestFormValue(t *testing.T) {
+	long := strings.Repeat("x", 2*maxMethodOverridePart)
+	tests := []struct {
+		form string
+		want string
+	}{
+		{"_method=PUT", "PUT"},
+		{"a=1&_method=delete&_method=put", "delete"},
+		{"a=1&%5Fmethod=PATCH", "PATCH"},
+		{"_method=PU%54", "PUT"},
+		{"a=1&_method", ""},
+		{"a=%zz&_method=%zz&_method=PUT", "PUT"},
+		{"a;b=1&_method=PUT", "PUT"},
+		{"_method=PUT;x&_method=DELETE", "DELETE"},
+		{"_method=" + long + "&_method=PUT", "PUT"},
+		{long + "=1&_method=PUT", "PUT"},
+		{"a=b=c&&_method=PUT", "PUT"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := formValue(strings.NewReader(tt.form), MethodOverrideField)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.form, err)
+		}
+		if got != tt.want {
+			t.Errorf("%q = %q, want %q", tt.form, got, tt.want)
+		}
+	}
+}
+
+func TestOverrideMethodLargeForm(t *testing.T) {
+	// The form spills to a temporary file, and the field comes last.
+	form := "data=" + strings.Repeat("a", 2*DefaultBodyMemoryThreshold) + "&" + MethodOverrideField + "=DELETE"
+	r := httptest.NewRequest("POST", "/", strings.NewReader(form))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	got, body, err := overrideMethod(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if got != "DELETE" {
+		t.Errorf("method = %s, want DELETE", got)
+	}
+}
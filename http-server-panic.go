@@ -0,0 +1,93 @@
+package skeleton
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+)
+
+// PanicError is returned by HttpServer.Serve when a handler panics and
+// HttpServer.RecoverPanics is enabled. It results in a 500 through
+// ErrorStatus.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+
+	// Goroutines is a dump of the stacks of all goroutines. It is only set
+	// if HttpServer.PanicGoroutineDump is enabled.
+	Goroutines []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// StatusCode returns http.StatusInternalServerError. It implements
+// StatusCoder.
+func (e *PanicError) StatusCode() int {
+	return http.StatusInternalServerError
+}
+
+// LogFields returns the structured fields used to log the panic.
+func (e *PanicError) LogFields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"Message": "Recovered from panic",
+		"Panic":   fmt.Sprint(e.Value),
+		"Stack":   string(e.Stack),
+	}
+	if e.Goroutines != nil {
+		fields["Goroutines"] = string(e.Goroutines)
+	}
+	return fields
+}
+
+// NewPanicError creates a PanicError for the recovered value v, capturing the
+// stack of the current goroutine. If dumpAll is true, the stacks of all
+// goroutines are captured as well.
+func NewPanicError(v interface{}, dumpAll bool) *PanicError {
+	e := &PanicError{Value: v, Stack: debug.Stack()}
+	if dumpAll {
+		e.Goroutines = allGoroutineStacks()
+	}
+	return e
+}
+
+// allGoroutineStacks returns the stacks of all goroutines, growing the buffer
+// until the dump fits.
+func allGoroutineStacks() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		if len(buf) >= 64<<20 {
+			return buf
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// abortIfPanicAfterWrite aborts the response if err is a *PanicError and the
+// response has already been started (or the connection hijacked), as a 500
+// can no longer be written. Panicking with http.ErrAbortHandler makes
+// net/http close the connection without logging a stack trace.
+func abortIfPanicAfterWrite(cw *statusCapturingResponseWriter, err error) {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) && (cw.Written() || cw.Hijacked()) {
+		panic(http.ErrAbortHandler)
+	}
+}
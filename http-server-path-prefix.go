@@ -0,0 +1,28 @@
+package skeleton
+
+import (
+	"strings"
+)
+
+// stripPathPrefix removes the provided prefix from path. The prefix is only
+// removed on a segment boundary, so that a prefix of /app does not match
+// /apple. If path does not carry the prefix (e.g., because a proxy has
+// already stripped it), it is returned unchanged.
+func stripPathPrefix(prefix, path string) string {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		return path
+	}
+
+	if !strings.HasPrefix(path, prefix) {
+		return path
+	}
+	rest := path[len(prefix):]
+	switch {
+	case rest == "":
+		return "/"
+	case rest[0] != '/':
+		return path
+	}
+	return rest
+}
@@ -0,0 +1,64 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MaxPathSegments is the maximum number of segments accepted in the path of
+// a request.
+const MaxPathSegments = 64
+
+var (
+	ErrUnsafePath = errors.New("unsafe path")
+)
+
+// checkPath rejects request paths which could confuse the router or the
+// serving of files: paths containing control characters (including null
+// bytes), dot-dot segments, or more than MaxPathSegments segments. The path
+// is checked after percent-decoding, so encoded forms (e.g., %2e%2e) are
+// caught as well.
+func checkPath(p string) error {
+	for i := 0; i < len(p); i++ {
+		if p[i] < 0x20 || p[i] == 0x7f {
+			return pathError("path contains control characters")
+		}
+	}
+	segments := strings.Split(p, "/")
+	if len(segments) > MaxPathSegments+1 {
+		return pathError("path has too many segments")
+	}
+	for _, s := range segments {
+		if s == ".." {
+			return pathError("path contains dot-dot segments")
+		}
+	}
+	return nil
+}
+
+func pathError(msg string) error {
+	return NewHttpError(http.StatusBadRequest, msg, ErrUnsafePath)
+}
+
+// SafeFilePath resolves a path taken from a request (e.g., a wildcard
+// parameter of a static file route) to a file path within root. The path is
+// cleaned as if rooted, so it can never escape root through dot-dot
+// segments. Paths containing null bytes or backslashes (which are separators
+// on Windows) are rejected with ErrUnsafePath.
+func SafeFilePath(root, requestPath string) (string, error) {
+	if strings.ContainsAny(requestPath, "\x00\\") {
+		return "", ErrUnsafePath
+	}
+	cleaned := path.Clean("/" + requestPath)
+	full := filepath.Join(root, filepath.FromSlash(cleaned))
+
+	// Defensive: ensure the result is still within root.
+	rel, err := filepath.Rel(root, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", ErrUnsafePath
+	}
+	return full, nil
+}
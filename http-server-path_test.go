@@ -0,0 +1,43 @@
+package skeleton
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func FuzzSafeFilePath(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"/",
+		"index.html",
+		"/assets/app.js",
+		"../etc/passwd",
+		"/a/../../b",
+		"a/./b//c/",
+		"..\\..\\windows",
+		"file\x00.txt",
+		"/%2e%2e/secret",
+	} {
+		f.Add(seed)
+	}
+
+	root := filepath.Join(f.TempDir(), "root")
+	f.Fuzz(func(t *testing.T, requestPath string) {
+		full, err := SafeFilePath(root, requestPath)
+		if err != nil {
+			if !errors.Is(err, ErrUnsafePath) {
+				t.Fatalf("SafeFilePath(%q) returned %v, want ErrUnsafePath", requestPath, err)
+			}
+			return
+		}
+		rel, err := filepath.Rel(root, full)
+		if err != nil {
+			t.Fatalf("SafeFilePath(%q) = %q, which is not relative to root: %v", requestPath, full, err)
+		}
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			t.Fatalf("SafeFilePath(%q) = %q, which escapes root", requestPath, full)
+		}
+	})
+}
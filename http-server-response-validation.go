@@ -0,0 +1,65 @@
+package skeleton
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// MaxValidatedResponseBytes is the maximum size of a response body which is
+// validated when HttpServer.ValidateResponses is enabled. Larger responses
+// are not validated.
+const MaxValidatedResponseBytes = 1 << 20
+
+// responseCapture holds a copy of the response body for validation.
+type responseCapture struct {
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (c *responseCapture) write(b []byte) {
+	if c.truncated {
+		return
+	}
+	if c.buf.Len()+len(b) > MaxValidatedResponseBytes {
+		c.truncated = true
+		c.buf.Reset()
+		return
+	}
+	c.buf.Write(b)
+}
+
+// validateResponse validates the captured response against the schema
+// documented for its status in doc. Only JSON responses are validated. If
+// doc documents responses but not the status which was written, that is
+// reported as a violation.
+func validateResponse(doc *RouteDoc, cw *statusCapturingResponseWriter) ValidationErrors {
+	if doc == nil || len(doc.Responses) == 0 || cw.capture == nil || cw.capture.truncated {
+		return nil
+	}
+	status := cw.Status()
+	if status == 0 {
+		return nil
+	}
+
+	rd, ok := doc.Responses[status]
+	if !ok {
+		return ValidationErrors{{Message: "status " + strconv.Itoa(status) + " is not documented"}}
+	}
+	if rd.Schema == nil {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(cw.Header().Get("Content-Type"))
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(cw.capture.buf.Bytes(), &v); err != nil {
+		return ValidationErrors{{Message: "invalid JSON body: " + err.Error()}}
+	}
+	return ValidateSchema(rd.Schema, v)
+}
@@ -0,0 +1,41 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// DefaultRetryAfter is the value, in seconds, of the Retry-After header sent
+// with 503 responses when a request is rejected due to load.
+const DefaultRetryAfter = 1
+
+var (
+	ErrRouteOverloaded = errors.New("route is at maximum concurrency")
+)
+
+// routeLimiters holds a ConcurrencyLimiter per route with MaxConcurrency
+// set. The limiters are keyed by the MetadataRoute, as that is the route
+// originally registered with the router.
+type routeLimiters struct {
+	m sync.Map
+}
+
+// acquire obtains a slot for the route. If the route is at capacity (after
+// waiting for meta.ConcurrencyTimeout), Retry-After is set on the response
+// and an *HttpError with status 503 is returned. Otherwise, the returned
+// function must be called to release the slot.
+func (l *routeLimiters) acquire(w http.ResponseWriter, r *http.Request, key MetadataRoute, meta RouteMetadata) (func(), error) {
+	v, ok := l.m.Load(key)
+	if !ok {
+		v, _ = l.m.LoadOrStore(key, NewConcurrencyLimiter(meta.MaxConcurrency))
+	}
+	limiter := v.(*ConcurrencyLimiter)
+
+	if !limiter.Acquire(r.Context(), meta.ConcurrencyTimeout) {
+		w.Header().Set("Retry-After", strconv.Itoa(DefaultRetryAfter))
+		return nil, NewHttpError(http.StatusServiceUnavailable, ErrRouteOverloaded.Error(), ErrRouteOverloaded)
+	}
+	return limiter.Release, nil
+}
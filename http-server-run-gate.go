@@ -0,0 +1,128 @@
+package skeleton
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultGateTimeout        = 30 * time.Second
+	DefaultGateInitialBackoff = 100 * time.Millisecond
+	DefaultGateMaxBackoff     = 5 * time.Second
+)
+
+// GatedRunner wraps a Runner so that it does not start until all of its
+// dependencies are healthy. This prevents a load balancer from routing
+// traffic to a server which is unable to serve it.
+//
+// On Run, the checks are retried with an exponential backoff until they all
+// pass or Timeout elapses. If the timeout elapses, Run returns the last
+// failure without running the wrapped Runner, which aborts startup when used
+// with skeleton.Run.
+type GatedRunner struct {
+	Runner
+
+	// Checks which need to pass before the Runner is started.
+	Checks []HealthCheck
+
+	// Timeout is the maximum amount of time to wait for the checks to pass.
+	// Defaults to DefaultGateTimeout.
+	Timeout time.Duration
+
+	// InitialBackoff is the delay before the first retry, which doubles on
+	// each subsequent retry up to MaxBackoff. Defaults to
+	// DefaultGateInitialBackoff and DefaultGateMaxBackoff respectively.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	ready    int32
+	stop     chan struct{}
+	initOnce sync.Once
+	stopOnce sync.Once
+}
+
+// NewGatedRunner creates a GatedRunner which waits for the provided checks
+// before running r.
+func NewGatedRunner(r Runner, timeout time.Duration, checks ...HealthCheck) *GatedRunner {
+	return &GatedRunner{
+		Runner:  r,
+		Checks:  checks,
+		Timeout: timeout,
+	}
+}
+
+// Run waits for the checks to pass and then runs the wrapped Runner.
+func (g *GatedRunner) Run(onShutdown ...func()) error {
+	if err := g.wait(); err != nil {
+		return err
+	}
+	atomic.StoreInt32(&g.ready, 1)
+	return g.Runner.Run(onShutdown...)
+}
+
+// Shutdown stops waiting for the checks (if still waiting) and shuts down the
+// wrapped Runner.
+func (g *GatedRunner) Shutdown(ctx context.Context) error {
+	g.stopOnce.Do(func() {
+		close(g.stopChan())
+	})
+	return g.Runner.Shutdown(ctx)
+}
+
+// Ready returns true once all checks have passed and the wrapped Runner has
+// been started.
+func (g *GatedRunner) Ready() bool {
+	return atomic.LoadInt32(&g.ready) == 1
+}
+
+// stopChan returns the channel which is closed on Shutdown, creating it if
+// necessary.
+func (g *GatedRunner) stopChan() chan struct{} {
+	g.initOnce.Do(func() {
+		g.stop = make(chan struct{})
+	})
+	return g.stop
+}
+
+// wait retries the checks until they pass, the timeout elapses or the runner
+// is shut down.
+func (g *GatedRunner) wait() error {
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = DefaultGateTimeout
+	}
+	backoff := g.InitialBackoff
+	if backoff <= 0 {
+		backoff = DefaultGateInitialBackoff
+	}
+	maxBackoff := g.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultGateMaxBackoff
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		err := RunHealthChecks(ctx, g.Checks...)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("dependencies not ready after %s: %w", timeout, err)
+		case <-g.stopChan():
+			return fmt.Errorf("shut down before dependencies were ready: %w", err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
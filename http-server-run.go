@@ -2,10 +2,12 @@ package skeleton
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
 	"os/signal"
 	"sync"
+	"syscall"
 	"time"
 )
 
@@ -21,6 +23,67 @@ type Runner interface {
 
 type RunRoutine func(<-chan struct{})
 
+// DefaultShutdownTimeout is the time given to the Runner and its routines to
+// shut down, unless the RunDelegate implements ShutdownTimeoutDelegate.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// ShutdownTimeoutDelegate is an optional interface for RunDelegates which
+// need a grace period other than DefaultShutdownTimeout (e.g., to allow long
+// uploads to complete). A value of 0 uses the default.
+type ShutdownTimeoutDelegate interface {
+	ShutdownTimeout() time.Duration
+}
+
+// SignalDelegate is an optional interface for RunDelegates which handle
+// signals beyond the ones triggering shutdown (SIGINT and SIGTERM), such as
+// SIGHUP to reload configuration.
+type SignalDelegate interface {
+	// Signals returns the additional signals to be notified of.
+	Signals() []os.Signal
+
+	// HandleSignal is called when one of the additional signals is received.
+	// It returns true if the server should shut down.
+	HandleSignal(sig os.Signal) bool
+}
+
+// waitForShutdownSignal blocks until a signal triggering shutdown is
+// received, or until the runner stops, in which case stopped is true and the
+// error it returned is returned. SIGKILL cannot be caught, so it is not handled; the process is
+// terminated immediately without shutting down.
+func waitForShutdownSignal(runDelegate RunDelegate, runErr <-chan error) (stopped bool, err error) {
+	signals := []os.Signal{os.Interrupt, syscall.SIGTERM}
+	sd, handlesSignals := runDelegate.(SignalDelegate)
+	if handlesSignals {
+		signals = append(signals, sd.Signals()...)
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, signals...)
+	defer signal.Stop(c)
+
+	for {
+		select {
+		case err := <-runErr:
+			return true, err
+		case sig := <-c:
+			if sig == os.Interrupt || sig == syscall.SIGTERM {
+				return false, nil
+			}
+			if handlesSignals && sd.HandleSignal(sig) {
+				return false, nil
+			}
+		}
+	}
+}
+
+// shutdownTimeout returns the grace period for the delegate.
+func shutdownTimeout(runDelegate RunDelegate) time.Duration {
+	if d, ok := runDelegate.(ShutdownTimeoutDelegate); ok && d.ShutdownTimeout() > 0 {
+		return d.ShutdownTimeout()
+	}
+	return DefaultShutdownTimeout
+}
+
 // RunDelegate is a helper which modifies the `Run` function.
 type RunDelegate interface {
 	// Routines are any routines that need to be run in parallel to the server.
@@ -49,8 +112,19 @@ type RunDelegate interface {
 // of the server, so that server shutdown also instructs these services to
 // shut down.
 //
-// Finally, it handles the CTRL+C signal from the OS to instruct the server
-// to shut down.
+// Finally, it handles the CTRL+C (SIGINT) and SIGTERM signals from the OS
+// (the latter being sent by process managers such as Kubernetes) to instruct
+// the server to shut down. Other signals can be handled through
+// SignalDelegate. The server and routines are given DefaultShutdownTimeout to
+// shut down; see ShutdownTimeoutDelegate to change it.
+//
+// Run returns once the server (including the cleanup deferred by its Run
+// method) and its routines have shut down. If the server
+// fails (e.g., the address is already in use), the routines are instructed to
+// shut down and the error is returned, so that the caller can clean up and
+// decide how to exit. An error is also returned if shutting down fails or
+// exceeds the grace period. If several of these fail, their errors are
+// joined with errors.Join.
 //
 // While the exemplary usage relates to an HTTP server, any server which
 // satisfies the Runner interface can be used.
@@ -61,12 +135,15 @@ type RunDelegate interface {
 //
 //	func main() {
 //			services := InitializeServices()
+//			defer services.Close()
 //			server := skeleton.NewLoggingHttpServer(...)
-//	     	skeleton.Run(server, &CustomDelegate{})
+//	     	if err := skeleton.Run(server, &CustomDelegate{}); err != nil {
+//	     		log.Fatal(err)
+//	     	}
 //	}
 //
 // ```
-func Run(runner Runner, runDelegate RunDelegate) {
+func Run(runner Runner, runDelegate RunDelegate) error {
 	// Set a default for runDelegate
 	if runDelegate == nil {
 		runDelegate = &NilRunDelegate{}
@@ -78,6 +155,7 @@ func Run(runner Runner, runDelegate RunDelegate) {
 	// case in a select statement, which should indicate shutdown.
 	//
 	ctxRegisterShutdown, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	// A WaitGroup to wait for any goroutines. All of those goroutines should
 	// use the context above.
@@ -92,29 +170,66 @@ func Run(runner Runner, runDelegate RunDelegate) {
 		}(routine)
 	}
 
-	// Run the server
+	// Run the server. The error is buffered, as it is not received if the
+	// server stops after a signal.
+	runErr := make(chan error, 1)
 	go func() {
-		if err := runDelegate.WrapRun(func() error {
+		runErr <- runDelegate.WrapRun(func() error {
 			return runner.Run(cancel)
-		}); err != nil && err != http.ErrServerClosed {
-			os.Exit(2)
-		}
+		})
 	}()
 
-	// Handle interrupt signal
-	cInterrupt := make(chan os.Signal, 1)
-	signal.Notify(cInterrupt, os.Interrupt, os.Kill)
-	<-cInterrupt
+	// Handle interrupt and termination signals
+	stopped, err := waitForShutdownSignal(runDelegate, runErr)
+
+	ctxShutdown, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout(runDelegate))
+	defer cancelShutdown()
+
+	// waitRoutines waits for the routines, but not beyond the grace period,
+	// so that a stuck routine does not prevent the process from exiting.
+	waitRoutines := func() error {
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctxShutdown.Done():
+			return ctxShutdown.Err()
+		}
+	}
 
-	ctxShutdown, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+	// The server failed (rather than being shut down), so it does not need to
+	// be shut down; only the routines do.
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		cancel()
+		return errors.Join(err, waitRoutines())
+	}
 
-	_ = runDelegate.WrapShutdown(func() error {
-		if err := runner.Shutdown(ctxShutdown); err != nil {
+	// waitRun waits for runner.Run to return, so that its deferred cleanup
+	// (e.g., the shutdown of the session store) has completed, but not
+	// beyond the grace period.
+	waitRun := func() error {
+		if stopped {
+			return nil
+		}
+		select {
+		case err := <-runErr:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
 			return err
+		case <-ctxShutdown.Done():
+			return ctxShutdown.Err()
 		}
-		wg.Wait()
-		return nil
-	})
+	}
 
+	// The routines are waited for even if the server fails to shut down.
+	return runDelegate.WrapShutdown(func() error {
+		shutdownErr := runner.Shutdown(ctxShutdown)
+		cancel()
+		return errors.Join(shutdownErr, waitRun(), waitRoutines())
+	})
 }
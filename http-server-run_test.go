@@ -0,0 +1,71 @@
+package skeleton
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// cleanupRunner is a Runner whose Run method defers a slow cleanup, as
+// HttpServer.Run defers the shutdown of the session store.
+type cleanupRunner struct {
+	stop    chan struct{}
+	cleaned int32
+}
+
+func (r *cleanupRunner) Run(onShutdown ...func()) error {
+	defer func() {
+		time.Sleep(50 * time.Millisecond)
+		atomic.StoreInt32(&r.cleaned, 1)
+	}()
+	<-r.stop
+	return http.ErrServerClosed
+}
+
+func (r *cleanupRunner) Shutdown(ctx context.Context) error {
+	close(r.stop)
+	return nil
+}
+
+func TestRunWaitsForDeferredCleanup(t *testing.T) {
+	// Keep SIGTERM from terminating the test process before Run listens to
+	// it.
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGTERM)
+	defer signal.Stop(c)
+
+	runner := &cleanupRunner{stop: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(runner, nil)
+	}()
+
+	self, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Run returned %v", err)
+			}
+			if atomic.LoadInt32(&runner.cleaned) == 0 {
+				t.Fatal("Run returned before the cleanup deferred by the runner")
+			}
+			return
+		case <-ticker.C:
+			_ = self.Signal(syscall.SIGTERM)
+		case <-timeout:
+			t.Fatal("Run did not return")
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package skeleton
+
+import (
+	"context"
+	"net/http"
+)
+
+// StdServer adapts a pre-configured *http.Server into a Runner, so that it
+// can be managed by Run (signals, routines and shutdown) without migrating to
+// HttpServer. Use StdServerRunner to create it.
+type StdServer struct {
+	Server *http.Server
+
+	// CertFile and KeyFile are passed to http.Server.ListenAndServeTLS. They
+	// can be omitted if Server.TLSConfig provides the certificates.
+	CertFile string
+	KeyFile  string
+}
+
+// StdServerRunner creates a Runner for the provided server.
+func StdServerRunner(srv *http.Server) *StdServer {
+	return &StdServer{Server: srv}
+}
+
+// Run the server. This is a blocking function. TLS is served if certificate
+// files are provided, or if Server.TLSConfig provides certificates.
+func (s *StdServer) Run(onShutdown ...func()) error {
+	for _, f := range onShutdown {
+		s.Server.RegisterOnShutdown(f)
+	}
+	if s.useTLS() {
+		return s.Server.ListenAndServeTLS(s.CertFile, s.KeyFile)
+	}
+	return s.Server.ListenAndServe()
+}
+
+// Shutdown the server.
+func (s *StdServer) Shutdown(ctx context.Context) error {
+	return s.Server.Shutdown(ctx)
+}
+
+func (s *StdServer) useTLS() bool {
+	if s.CertFile != "" && s.KeyFile != "" {
+		return true
+	}
+	c := s.Server.TLSConfig
+	return c != nil && (len(c.Certificates) > 0 || c.GetCertificate != nil || c.GetConfigForClient != nil)
+}
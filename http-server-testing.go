@@ -0,0 +1,62 @@
+package skeleton
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+)
+
+// TestRequest serves a synthetic request through the provided handler (e.g.,
+// a server), without a listener, and returns the recorded response. It is
+// intended for the tests of applications. The request can be further
+// customized by using httptest directly.
+func TestRequest(h http.Handler, method, path string, body io.Reader) (*httptest.ResponseRecorder, error) {
+	r, err := http.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	// Match the remote address and host set by httptest.NewRequest.
+	r.RemoteAddr = "192.0.2.1:1234"
+	if r.Host == "" {
+		r.Host = "example.com"
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec, nil
+}
+
+// TestRequest runs a synthetic request through the full ServeHTTP pipeline
+// of the server. See TestRequest.
+//
+// ```
+//
+//	rec, err := server.TestRequest(http.MethodGet, "/users/1", nil)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	if rec.Code != http.StatusOK {
+//	    t.Errorf("unexpected status %d", rec.Code)
+//	}
+//
+// ```
+func (s *HttpServer[Ctx, R]) TestRequest(method, path string, body io.Reader) (*httptest.ResponseRecorder, error) {
+	return TestRequest(s, method, path, body)
+}
+
+// TestRequest runs a synthetic request through the full ServeHTTP pipeline
+// of the logging server. See TestRequest.
+func (s *LoggingHttpServer[Ctx, R]) TestRequest(method, path string, body io.Reader) (*httptest.ResponseRecorder, error) {
+	return TestRequest(s, method, path, body)
+}
+
+// TestRequestWithID is a version of TestRequest which also returns the
+// request ID which was generated for the request, so that it can be matched
+// against the logs.
+func (s *LoggingHttpServer[Ctx, R]) TestRequestWithID(method, path string, body io.Reader) (*httptest.ResponseRecorder, string, error) {
+	rec, err := s.TestRequest(method, path, body)
+	if err != nil {
+		return nil, "", err
+	}
+	return rec, rec.Header().Get(s.requestIDHeader()), nil
+}
@@ -0,0 +1,38 @@
+package skeleton
+
+import (
+	"net/http"
+	"time"
+)
+
+// Timeouts are the timeouts of the underlying http.Server. A zero value
+// keeps the default of net/http, which is no timeout. Setting at least
+// ReadHeader is recommended in production, as it protects the server from
+// clients which send headers slowly (slowloris).
+//
+// Write bounds the time taken to read the request and write the response,
+// so it should be longer than the slowest handler (e.g., of a streaming
+// response).
+type Timeouts struct {
+	ReadHeader time.Duration // See http.Server.ReadHeaderTimeout.
+	Read       time.Duration // See http.Server.ReadTimeout.
+	Write      time.Duration // See http.Server.WriteTimeout.
+	Idle       time.Duration // See http.Server.IdleTimeout.
+}
+
+// apply sets the timeouts on the server. Timeouts which are not set are left
+// unchanged.
+func (t Timeouts) apply(srv *http.Server) {
+	if t.ReadHeader > 0 {
+		srv.ReadHeaderTimeout = t.ReadHeader
+	}
+	if t.Read > 0 {
+		srv.ReadTimeout = t.Read
+	}
+	if t.Write > 0 {
+		srv.WriteTimeout = t.Write
+	}
+	if t.Idle > 0 {
+		srv.IdleTimeout = t.Idle
+	}
+}
@@ -0,0 +1,48 @@
+package skeleton
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// LoadTLSConfig creates a TLS configuration serving the certificate and key
+// in the provided PEM files, for use as HttpServer.TLSConfig.
+func LoadTLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// listenAndServe starts the server, over TLS if a TLSConfig is provided. It
+// serves the Listener if one is provided, and listens on the address of the
+// server otherwise. The handler of the server is wrapped in the
+// HandlerMiddleware beforehand, and ConfigureServer is called last.
+func (s *HttpServer[Ctx, R]) listenAndServe(server *http.Server) error {
+	server.Handler = WithMiddleware(server.Handler, s.HandlerMiddleware...)
+	s.applyH2C(server)
+	if s.TLSConfig != nil {
+		server.TLSConfig = s.TLSConfig.Clone()
+	}
+	if s.Listener != nil {
+		server.Addr = s.Listener.Addr().String()
+	}
+	if s.ConfigureServer != nil {
+		s.ConfigureServer(server)
+	}
+
+	if s.Listener != nil {
+		if server.TLSConfig != nil {
+			return server.ServeTLS(s.Listener, "", "")
+		}
+		return server.Serve(s.Listener)
+	}
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
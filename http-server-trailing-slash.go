@@ -0,0 +1,49 @@
+package skeleton
+
+import (
+	"net/http"
+	"strings"
+)
+
+// redirectTrailingSlash redirects the request to its path with the trailing
+// slash added or removed, if a route matches that path. It returns true if
+// the request was redirected.
+func (s *HttpServer[Ctx, R]) redirectTrailingSlash(w http.ResponseWriter, r *http.Request) bool {
+	path := r.URL.Path
+	if path == "/" || path == "" {
+		return false
+	}
+	path = toggleTrailingSlash(path)
+
+	// A path such as //example.com would be a redirect to another host.
+	if strings.HasPrefix(path, "//") {
+		return false
+	}
+
+	route, err := s.R.Match(r.Method, stripPathPrefix(s.PathPrefix, path))
+	if err != nil || isNilRoute(route) {
+		return false
+	}
+
+	// The location keeps the escaping of the original path.
+	location := toggleTrailingSlash(r.URL.EscapedPath())
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+	status := http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		status = http.StatusMovedPermanently
+	}
+	w.Header().Set("Location", location)
+	w.WriteHeader(status)
+	return true
+}
+
+// toggleTrailingSlash removes the trailing slash of the path, or adds one if
+// there is none.
+func toggleTrailingSlash(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path + "/"
+}
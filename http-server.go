@@ -2,8 +2,14 @@ package skeleton
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"net"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -17,6 +23,30 @@ type Route[R any] interface {
 	GetHandler() func(R)
 }
 
+// PatternRoute is an optional interface for routes which are able to report
+// the pattern they were registered with (e.g., /users/:id), as opposed to the
+// path which was requested. This is useful for logging, as it keeps the
+// cardinality of logged paths low.
+type PatternRoute interface {
+	Pattern() string
+}
+
+// ParamsRoute is an optional interface for routes which are able to report
+// the parameters extracted from the requested path (e.g., id for
+// /users/:id).
+type ParamsRoute interface {
+	PathParams() map[string]string
+}
+
+// routePattern returns the pattern of the provided route if it implements
+// PatternRoute. Otherwise, an empty string is returned.
+func routePattern(route any) string {
+	if p, ok := route.(PatternRoute); ok {
+		return p.Pattern()
+	}
+	return ""
+}
+
 // Router defines the methods required by HttpServer for route handler
 // organization. If the desired router does not fit this mold, simply wrap it
 // in another struct to force it to comply.
@@ -43,9 +73,42 @@ type Router[Ctx any, R Route[Ctx]] interface {
 	// be desired from parsing the path. For example, if the router allows
 	// route patterns with placeholders such as :id, the matching ID can be
 	// provided within this returned R
+	//
+	// If a route matches the path but not the method, Match should return a
+	// *MethodNotAllowedError listing the allowed methods, so that the server
+	// responds with a 405 rather than a 404.
 	Match(method, path string) (R, error)
 }
 
+// isNilRoute returns true if the route returned by Router.Match is nil. As R
+// is usually a pointer, a nil R is not a nil interface, so reflect is used.
+func isNilRoute(route any) bool {
+	if route == nil {
+		return true
+	}
+	v := reflect.ValueOf(route)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+// RouteInfo describes a route added to a Router.
+type RouteInfo struct {
+	Method string
+	Path   string
+
+	// Route is the route which was added.
+	Route any
+}
+
+// RouteLister is an optional interface for routers which are able to list
+// the routes which were added to them, in the order they were added.
+type RouteLister interface {
+	Routes() []RouteInfo
+}
+
 // HttpServer describes an extensible and basic http server implementation.
 // User can decide what Router, SessionStore, and context to provide all route
 // handlers.
@@ -60,11 +123,223 @@ type Router[Ctx any, R Route[Ctx]] interface {
 type HttpServer[Ctx any, R Route[Ctx]] struct {
 	Addr   string
 	Server *http.Server   // The base http Server.
-	S      SessionStore   // Storage for session information
+	S      SessionStore   // Storage for session information. May be nil.
 	R      Router[Ctx, R] // Router for organization route handlers.
 
+	// MethodOverride allows POST requests to be matched as PUT, PATCH or
+	// DELETE requests, based on the X-HTTP-Method-Override header or the
+	// _method form field. This supports HTML forms, which can only send GET
+	// and POST requests.
+	MethodOverride bool
+
+	// BeforeWrite, if provided, is called exactly once per response, just
+	// before the header is written. It can be used to add computed headers
+	// (e.g., a signature) or debug headers to every response. The provided
+	// ResponseWriter should only be used to modify the header.
+	BeforeWrite func(w http.ResponseWriter, r *http.Request)
+
+	// PathPrefix is the path at which the server is mounted (e.g., /app when
+	// deployed behind a proxy at that path). It is removed from the request
+	// path before matching, so that routes can be defined relative to the
+	// mount point. Requests whose path does not carry the prefix are matched
+	// as is. The request itself is not modified, so logs retain the full
+	// path.
+	PathPrefix string
+
+	// DetectEmptyResponse enables detection of handlers which return without
+	// writing anything, which would otherwise result in a 200 with an empty
+	// body. The LoggingHttpServer logs a warning for such requests. If
+	// EmptyResponseStatus is also set (e.g., http.StatusNoContent), it is
+	// written as the response.
+	DetectEmptyResponse bool
+	EmptyResponseStatus int
+
+	// RouteInterceptor, if provided, is called with the matched route before
+	// the context is generated. The returned route is dispatched instead,
+	// allowing the handler to be replaced based on attributes of the request
+	// (e.g., for A/B testing or canary releases). Return the provided route
+	// to leave it unchanged. Returning nil results in a 404, as if no route
+	// had matched.
+	RouteInterceptor func(route R, r *http.Request) R
+
+	// RecoverPanics recovers panics raised while serving a request, which are
+	// then returned from Serve as a *PanicError. ServeHTTP responds with a
+	// 500, and the LoggingHttpServer logs the panic value and stack through
+	// the request logger. If the handler had already started the response,
+	// it is aborted instead, so that the client sees it as incomplete.
+	// PanicGoroutineDump additionally captures the stacks of all goroutines,
+	// which helps to diagnose deadlocks and leaks. As this is expensive, it
+	// is off by default.
+	RecoverPanics      bool
+	PanicGoroutineDump bool
+
+	// Authenticate determines whether a request is authenticated. It is only
+	// called for routes with RouteMetadata.RequireAuth set, after the session
+	// has been retrieved. A non-nil error rejects the request; it results in
+	// a 401 unless the error implements StatusCoder. If nil, a request is
+	// authenticated if its session holds a value under AuthSessionKey.
+	Authenticate func(r *http.Request, sess Session) error
+
+	// CoalesceKey returns the key identifying identical requests for routes
+	// with RouteMetadata.Coalesce. Provide it if responses vary on anything
+	// beyond the method, path and query (e.g., Accept-Language).
+	CoalesceKey func(r *http.Request) string
+
+	// AllowMalformedRequests disables the rejection (with a 400) of requests
+	// with an empty method, a path which is not absolute, or an unsafe path
+	// (containing control characters, dot-dot segments or more than
+	// MaxPathSegments segments). Enable it when the server sits behind a
+	// proxy forwarding unusual paths.
+	AllowMalformedRequests bool
+
+	// HeaderExtractors copies request headers (the keys) into the request
+	// context under the provided ContextKey (e.g., X-Tenant-ID to "tenant"),
+	// so that handlers can retrieve them uniformly through HeaderValue. The
+	// LoggingHttpServer also adds them to the fields of the request logger,
+	// except under the keys of the fields it sets itself (ID, Method and
+	// Path). Missing headers are skipped, and values already present in the
+	// context take precedence over headers.
+	HeaderExtractors map[string]ContextKey
+
+	// Services is the fixed set of service tags which routes may declare
+	// through RouteMetadata.Service. Tags outside of this set are reported
+	// as UnregisteredService, which bounds the cardinality of logs and
+	// metrics.
+	Services []string
+
+	// Correlation enables correlation IDs, which are read from the
+	// CorrelationIDHeader (or generated if absent), stored in the request
+	// context and echoed in the response. See CorrelationID.
+	Correlation bool
+
+	// ResolveTier, if provided, resolves the tier of the request (e.g., from
+	// its API key), which selects the handler variant of routes implementing
+	// TieredRoute. It is called after Authenticate, so it can rely on the
+	// identity of the request. The tier is stored in the request context
+	// (see Tier); requests resolving to an empty tier are in DefaultTier.
+	// Routes without a variant for the tier serve their default handler.
+	ResolveTier func(r *http.Request, sess Session) string
+
+	// ValidateResponses validates JSON responses against the schemas
+	// documented in RouteMetadata.Doc, to catch handlers which drift from
+	// their contract. Violations are never returned to the client; the
+	// LoggingHttpServer logs them as warnings with the route and offending
+	// fields. As responses are buffered (up to MaxValidatedResponseBytes), it
+	// should only be enabled outside of production (e.g., in CI or staging).
+	ValidateResponses bool
+
+	// TLSConfig, if provided, serves the server over HTTPS. It must provide
+	// the certificates (e.g., through LoadTLSConfig or GetCertificate).
+	TLSConfig *tls.Config
+
+	// ConfigureServer, if provided, is called with the underlying
+	// http.Server once Run has configured it, before it starts serving. It
+	// allows any field to be tuned (e.g., ConnState, BaseContext, ErrorLog or
+	// MaxHeaderBytes). The server is served over TLS if its TLSConfig is set
+	// once ConfigureServer returns; it must then provide the certificates.
+	ConfigureServer func(server *http.Server)
+
+	// HandlerMiddleware is standard net/http middleware which Run wraps
+	// around the server (see WithMiddleware), the first being the outermost.
+	// Unlike middleware registered with Use, it runs for every request,
+	// including those which do not match a route, and outside of the
+	// logging of the LoggingHttpServer.
+	HandlerMiddleware []func(http.Handler) http.Handler
+
+	// DrainDelay is the time waited on shutdown between the server being
+	// marked as draining (so that DrainingHealthCheck fails) and it no longer
+	// accepting connections. It should cover the time taken by the load
+	// balancer to notice the failing readiness check. It counts against the
+	// grace period of the shutdown (see ShutdownTimeoutDelegate).
+	DrainDelay time.Duration
+
+	// EnableH2C serves HTTP/2 over cleartext (h2c) in addition to HTTP/1,
+	// for service-to-service traffic which is not encrypted (e.g., behind a
+	// service mesh). It has no effect if TLSConfig is provided, as HTTP/2 is
+	// then negotiated through TLS.
+	EnableH2C bool
+
+	// Listener, if provided, is served instead of listening on Addr (e.g., a
+	// socket passed by systemd, or a listener on port 0 in tests, whose Addr
+	// reports the chosen port). The server closes it when it shuts down.
+	Listener net.Listener
+
+	// JSONErrors renders errors as JSON (see JSONErrorHandler) rather than
+	// text, when ErrorHandler is not provided.
+	JSONErrors bool
+
+	// Fallback, if provided, serves requests which do not match any route,
+	// instead of responding with a 404 (e.g., an SPA serving a frontend).
+	// Requests whose path matches a route with a different method still
+	// result in a 405.
+	Fallback http.Handler
+
+	// MaxBodyBytes, if set, limits the size of request bodies. Requests whose
+	// Content-Length exceeds it are rejected with a 413. Otherwise, reads
+	// beyond the limit fail with a 413 *HttpError wrapping ErrBodyTooLarge,
+	// and a 413 is rendered through the error handler if the handler
+	// returns without responding.
+	MaxBodyBytes int64
+
+	// RedirectTrailingSlash redirects requests which do not match any route,
+	// but would with a trailing slash added or removed (e.g., /users/ when
+	// /users is registered), to that path. GET and HEAD requests receive a
+	// 301, and other methods a 308 so that the method and body are kept.
+	RedirectTrailingSlash bool
+
+	// ErrorHandler, if provided, renders the errors returned by Serve (e.g.,
+	// as JSON), instead of DefaultErrorHandler. Use ErrorStatus to determine
+	// the status, and errors.Is(err, ErrNoRoute) to detect unknown routes.
+	// Errors are still logged by the LoggingHttpServer before it is called.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+	// RequestTimeout, if set, bounds the context of each request with
+	// context.WithTimeout, so that handlers passing it to their calls (see
+	// RequestContext) stop working once it elapses. Routes can override it
+	// through TimeoutRoute or RouteMetadata.Timeout. Handlers need to observe
+	// the context; they are not interrupted. If the deadline passes before
+	// the handler responds, the error handler responds with a 504.
+	RequestTimeout time.Duration
+
+	// SessionName, if provided, selects the session of the request by name
+	// (e.g., separate admin and public sessions, based on the path). It
+	// requires the SessionStore to implement NamedSessionStore. Returning an
+	// empty string uses the default session of the store.
+	SessionName func(r *http.Request) string
+
+	// Timeouts are applied to the underlying http.Server when running the
+	// server. See Timeouts.
+	Timeouts Timeouts
+
+	// IdleTimeouts, if provided, closes idle keep-alive connections sooner
+	// when no valid session has been seen on them. See ConnIdleTimeouts.
+	IdleTimeouts *ConnIdleTimeouts
+
+	// Metrics, if provided, records the count, latency and status of the
+	// requests by route. See RequestMetrics.
+	Metrics *RequestMetrics
+
+	// LoadShedder, if provided, limits the number of requests processed at
+	// once and sheds low-priority requests as the server nears capacity.
+	LoadShedder *LoadShedder
+
+	// TrustedProxies are the proxies whose forwarded headers are trusted when
+	// determining the client IP of a request (e.g., for logging). See
+	// ClientIP.
+	TrustedProxies TrustedProxies
+
+	// Maintenance, if provided, allows the server to be put into maintenance
+	// at runtime. See MaintenanceMode.
+	Maintenance *MaintenanceMode
+
 	// Delegate should be provided by the application
 	Delegate HttpServerDelegate[Ctx, R]
+
+	middleware    []Middleware[Ctx]
+	routeLimiters routeLimiters
+	coalescer     coalescer
+	inFlight      atomic.Int64
+	draining      atomic.Bool
 }
 
 // NewHttpServer creates a new HTTP server.
@@ -82,57 +357,274 @@ func NewHttpServer[Ctx any, R Route[Ctx]](
 	}
 }
 
-// Run the server. This is a blocking function.
+// Run the server. This is a blocking function. An error is returned if Addr
+// is malformed; see NormalizeAddr for the accepted forms. The Listener is
+// served instead of Addr if provided. The server is served over TLS if
+// TLSConfig is provided.
 func (s *HttpServer[Ctx, R]) Run(onShutdown ...func()) error {
 	if s.S != nil {
 		defer s.S.Shutdown()
 	}
 
 	// This is so that we can handle cleanup
-	s.Server = &http.Server{
-		Addr:    s.Addr,
-		Handler: s,
-	}
-	for _, f := range onShutdown {
-		s.Server.RegisterOnShutdown(f)
+	server, err := newBaseServer(s.Addr, s, onShutdown...)
+	if err != nil {
+		return err
 	}
-	return s.Server.ListenAndServe()
+	s.Timeouts.apply(server)
+	s.IdleTimeouts.apply(server)
+	s.Server = server
+	return s.listenAndServe(server)
 }
 
-// Shutdown the server,
+// Shutdown the server. The server is marked as draining first (see
+// Draining), and DrainDelay is waited before connections are closed.
 func (s *HttpServer[Ctx, R]) Shutdown(ctx context.Context) error {
+	s.drain(ctx)
 	if s.Server == nil {
 		return nil
 	}
 	return s.Server.Shutdown(ctx)
 }
 
+// InFlight returns the number of requests currently being served.
+func (s *HttpServer[Ctx, R]) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
 // ServeWithDelegate is a version of serve allowing for a custom delegate to be
 // provided.
-func (s *HttpServer[Ctx, R]) ServeWithDelegate(w http.ResponseWriter, r *http.Request, delegate HttpServerDelegate[Ctx, R]) error {
+func (s *HttpServer[Ctx, R]) ServeWithDelegate(w http.ResponseWriter, r *http.Request, delegate HttpServerDelegate[Ctx, R]) (err error) {
+	s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+
+	cw := s.captureResponse(w, r)
+	w = cw
+
+	if s.RecoverPanics {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			// ErrAbortHandler is used to deliberately abort a response, and
+			// is handled by net/http.
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			err = NewPanicError(v, s.PanicGoroutineDump)
+		}()
+	}
+
+	// Reject malformed requests and requests with ambiguous framing before
+	// doing any work.
+	if !s.AllowMalformedRequests {
+		if err := checkRequestLine(r); err != nil {
+			return err
+		}
+		if err := checkPath(r.URL.Path); err != nil {
+			return err
+		}
+	}
+	if err := checkFraming(r); err != nil {
+		return err
+	}
+
+	// Limit the size of the body before anything (e.g., the method override
+	// or the handler) reads it.
+	var limited *limitedBody
+	if s.MaxBodyBytes > 0 {
+		if limited, err = limitBody(w, r, s.MaxBodyBytes); err != nil {
+			return err
+		}
+	}
+
+	if len(s.HeaderExtractors) > 0 {
+		r = extractHeaders(r, s.HeaderExtractors)
+	}
+	if s.Correlation {
+		r = correlate(w, r)
+	}
+
+	// Politely reject traffic while in maintenance.
+	if s.Maintenance.serve(w, r) {
+		return nil
+	}
+
 	// Initialize the session. If there is an error, return the default error
 	// message.
 	var sess Session
 	if s.S != nil {
-		var err error
-
 		// Attempt to retrieve the session. Could be nil.
-		sess, err = s.S.Get(r)
+		sess, err = s.getSession(r)
 		if err != nil {
 			return NewSessionError("unable to get session", err)
 		}
+		if sess != nil && !sess.IsNew() {
+			MarkConnAuthenticated(r.Context())
+		}
+		if sess != nil {
+			r = r.WithContext(WithSession(r.Context(), sess))
+		}
+	}
+
+	// Apply the method override, if enabled. The request passed on to the
+	// delegate reflects the overridden method.
+	if s.MethodOverride {
+		method, body, err := overrideMethod(r)
+		if body != nil {
+			defer body.Close()
+		}
+		if err != nil {
+			return err
+		}
+		if method != r.Method {
+			r = r.WithContext(r.Context())
+			r.Method = method
+		}
 	}
 
 	// Retrieve a route, if possible.
-	route, err := s.R.Match(r.Method, r.URL.Path)
+	route, err := s.R.Match(r.Method, stripPathPrefix(s.PathPrefix, r.URL.Path))
+	if err == nil && isNilRoute(route) {
+		err = ErrNoRoute
+	}
 	if err != nil {
-		return ErrNoRoute
+		var mErr *MethodNotAllowedError
+		if errors.As(err, &mErr) {
+			w.Header().Set("Allow", strings.Join(mErr.Allowed, ", "))
+			return err
+		}
+		if s.RedirectTrailingSlash && s.redirectTrailingSlash(w, r) {
+			return nil
+		}
+		if s.Fallback != nil {
+			s.Fallback.ServeHTTP(w, r)
+			return nil
+		}
+		return NewRouteNotFoundError(r.Method, r.URL.Path, err)
+	}
+	if !routeEnabled(route) {
+		return NewRouteNotFoundError(r.Method, r.URL.Path, ErrRouteDisabled)
+	}
+	if s.RouteInterceptor != nil {
+		route = s.RouteInterceptor(route, r)
+		if isNilRoute(route) {
+			return NewRouteNotFoundError(r.Method, r.URL.Path, ErrNoRoute)
+		}
+	}
+
+	meta, metaRoute := routeMetadata(route)
+	cw.pattern = routePattern(route)
+	cw.service = serviceTag(route, s.Services)
+
+	// Reject unauthenticated requests to protected routes.
+	if meta.RequireAuth {
+		if err := s.authenticate(r, sess); err != nil {
+			return err
+		}
+		MarkConnAuthenticated(r.Context())
+	}
+
+	// Bound the time spent serving the request. The context of the request
+	// passed to the delegate (and so to the handler) carries the deadline.
+	var deadlineCtx context.Context
+	if timeout := requestTimeout(s.RequestTimeout, route, meta); timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+		deadlineCtx = ctx
+	}
+
+	// Resolve the tier, which selects the handler variant of the route.
+	r = s.resolveTier(r, sess)
+	var handlerErr error
+	handler := errorRouteHandler(route, routeHandler[Ctx](route, r), &handlerErr)
+	handler = s.applyMiddleware(route, handler)
+
+	// Shed low-priority requests under load.
+	if s.LoadShedder != nil {
+		release, err := s.LoadShedder.admit(w, routePriority(meta))
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	// Enforce the concurrency limit of the route, if any.
+	if meta.MaxConcurrency > 0 {
+		release, err := s.routeLimiters.acquire(w, r, metaRoute, meta)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	// Capture the response, so that it can be checked against its contract.
+	if s.ValidateResponses && meta.Doc != nil && len(meta.Doc.Responses) > 0 {
+		cw.capture = &responseCapture{}
+		defer func() {
+			cw.violations = validateResponse(meta.Doc, cw)
+			cw.capture = nil
+		}()
 	}
 
 	// Generate the context. It is assumed here that Generator is provided, as
 	// it is required.
-	ctx := delegate.Generate(w, r, route, sess)
-	route.GetHandler()(ctx)
+	if meta.Coalesce && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		key := coalesceKey(r)
+		if s.CoalesceKey != nil {
+			key = s.CoalesceKey(r)
+		}
+		if err := s.coalescer.serve(w, key, func(rec *responseRecorder) error {
+			handler(delegate.Generate(rec, r, route, sess))
+			if handlerErr != nil {
+				return handlerErr
+			}
+			if !rec.written() {
+				return unwrittenError(limited, deadlineCtx)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	} else {
+		ctx := delegate.Generate(w, r, route, sess)
+		handler(ctx)
+	}
+
+	if handlerErr != nil {
+		return handlerErr
+	}
+	if cw.Written() || cw.Hijacked() {
+		return nil
+	}
+	if err := unwrittenError(limited, deadlineCtx); err != nil {
+		return err
+	}
+	if s.DetectEmptyResponse {
+		cw.empty = true
+		if s.EmptyResponseStatus != 0 {
+			cw.WriteHeader(s.EmptyResponseStatus)
+		}
+	}
+	return nil
+}
+
+// unwrittenError returns the error for a request whose handler returned
+// without writing a response, if the handler stopped because of the limits
+// of the request.
+func unwrittenError(limited *limitedBody, deadlineCtx context.Context) error {
+	// A handler which stopped reading because the body exceeded
+	// MaxBodyBytes results in a 413.
+	if limited.Exceeded() {
+		return NewHttpError(http.StatusRequestEntityTooLarge, ErrBodyTooLarge.Error(), ErrBodyTooLarge)
+	}
+
+	// A handler which gave up because of the deadline results in a 504.
+	if deadlineCtx != nil && deadlineCtx.Err() == context.DeadlineExceeded {
+		return NewHttpError(http.StatusGatewayTimeout, "request timed out", deadlineCtx.Err())
+	}
 	return nil
 }
 
@@ -147,23 +639,117 @@ func (s *HttpServer[Ctx, R]) ServeWithDelegate(w http.ResponseWriter, r *http.Re
 // In the case that there is an error in retrieving the session, a SessionError
 // object will be returned. Use SessionError.Unwrap to view the underlying error.
 //
-// In the case that a route could not be found, ErrNoRoute will be returned.
+// In the case that a route could not be found, a *RouteNotFoundError will be
+// returned, which carries the attempted method and path. Use
+// errors.Is(err, ErrNoRoute) to check for this case.
+//
+// Requests which are rejected by the server (e.g., due to ambiguous framing or
+// a route being at its concurrency limit) result in an *HttpError. Use
+// ErrorStatus to retrieve the status code.
+//
+// If RecoverPanics is enabled, a panic in the handler results in a
+// *PanicError.
 func (s *HttpServer[Ctx, R]) Serve(w http.ResponseWriter, r *http.Request) error {
 	return s.ServeWithDelegate(w, r, s.Delegate)
 }
 
+// getSession retrieves the session of the request, selecting it by name if
+// SessionName is provided and the store supports it.
+func (s *HttpServer[Ctx, R]) getSession(r *http.Request) (Session, error) {
+	if s.SessionName != nil {
+		if ns, ok := s.S.(NamedSessionStore); ok {
+			if name := s.SessionName(r); name != "" {
+				return ns.GetNamed(r, name)
+			}
+		}
+	}
+	return s.S.Get(r)
+}
+
+// captureResponse wraps the ResponseWriter so that the status code and size
+// of the response are recorded, and the BeforeWrite hook is run. If w has
+// already been wrapped, the existing wrapper is returned.
+func (s *HttpServer[Ctx, R]) captureResponse(w http.ResponseWriter, r *http.Request) *statusCapturingResponseWriter {
+	cw := newStatusCapturingResponseWriter(w)
+	if s.BeforeWrite != nil && cw.beforeWrite == nil {
+		cw.beforeWrite = func() {
+			s.BeforeWrite(cw.ResponseWriter, r)
+		}
+	}
+	return cw
+}
+
 // ServeHTTP implements the http.Handler interface.
 func (s *HttpServer[Ctx, R]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, _ = s.ServeAndRender(w, r)
+}
+
+// ServeAndRender serves the request as ServeHTTP does, rendering any error
+// through the error handler (see ErrorHandler), but also returns the status
+// which was written and the error returned by Serve. This allows the server
+// to be composed with other handlers (e.g., a proxy falling back to another
+// upstream on a 404). A status of 0 is returned if the connection was
+// hijacked.
+func (s *HttpServer[Ctx, R]) ServeAndRender(w http.ResponseWriter, r *http.Request) (int, error) {
+	cw := s.captureResponse(w, r)
+	w = cw
+
+	if s.Metrics != nil {
+		done := s.Metrics.begin()
+		defer func() { done(r, cw) }()
+	}
+
 	err := s.Serve(w, r)
-	if err == nil {
-		return
+	if err != nil {
+		if ErrorStatus(err) == http.StatusInternalServerError {
+			abortIfPanicAfterWrite(cw, err)
+		}
+		s.writeError(w, r, err)
 	}
+	return writtenStatus(cw), err
+}
+
+// writtenStatus returns the status written to the client. As net/http
+// responds with a 200 if the handler did not write a header, that is
+// reported unless the connection was hijacked.
+func writtenStatus(cw *statusCapturingResponseWriter) int {
+	switch {
+	case cw.Hijacked():
+		return 0
+	case !cw.Written():
+		return http.StatusOK
+	}
+	return cw.Status()
+}
 
-	if err == ErrNoRoute {
-		w.WriteHeader(http.StatusNotFound)
+// writeError renders the error through the ErrorHandler, if provided, or
+// the default error rendering otherwise.
+func (s *HttpServer[Ctx, R]) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	// The response can no longer be changed (e.g., the handler of an
+	// ErrorReturningRoute returned an error after writing).
+	if cw, ok := w.(*statusCapturingResponseWriter); ok && (cw.Written() || cw.Hijacked()) {
 		return
 	}
+	switch {
+	case s.ErrorHandler != nil:
+		s.ErrorHandler(w, r, err)
+	case s.JSONErrors:
+		JSONErrorHandler(w, r, err)
+	default:
+		DefaultErrorHandler(w, r, err)
+	}
+}
 
+// DefaultErrorHandler renders errors returned by Serve when no ErrorHandler
+// is provided. The status is determined by ErrorStatus; a 404 or other
+// client error is written without a body, while a 500 is written with a
+// generic message.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	status := ErrorStatus(err)
+	if status != http.StatusInternalServerError {
+		w.WriteHeader(status)
+		return
+	}
 	w.WriteHeader(http.StatusInternalServerError)
 	w.Write([]byte("The system services are temporarily unavailable at the moment."))
 }
@@ -0,0 +1,30 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+func TestRouteInterceptorNilRoute(t *testing.T) {
+	router := GoRouter[*gorouter.RouteContext]()
+	router.AddRoute(GoRoute("GET", "/beta", func(ctx *gorouter.RouteContext) {
+		ctx.W.WriteHeader(http.StatusOK)
+	}))
+	server := NewHttpServer[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]](
+		"", nil, router, &GoHttpServerDelegate{})
+	server.RouteInterceptor = func(route *GoRouterRoute[*gorouter.RouteContext], r *http.Request) *GoRouterRoute[*gorouter.RouteContext] {
+		return nil
+	}
+
+	status, err := server.ServeAndRender(httptest.NewRecorder(), httptest.NewRequest("GET", "/beta", nil))
+	if !errors.Is(err, ErrNoRoute) {
+		t.Errorf("error = %v, want ErrNoRoute", err)
+	}
+	if status != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", status, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,110 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrJSONStreamClosed = errors.New("json array stream is closed")
+)
+
+// JSONArrayStream writes a JSON array to the response one item at a time, so
+// that large collections do not need to be buffered in memory. The response
+// is flushed after every item so that clients receive data progressively.
+//
+// Once the first item has been written, the status and the opening bracket
+// have been sent, and the response can no longer be rolled back (e.g., into
+// an error response). If an item fails to encode, it is skipped and the error
+// is returned; the array written so far remains valid. If writing to the
+// client fails, the error is returned by every subsequent call. Close must
+// always be called to terminate the array.
+//
+// ```
+//
+//	stream := skeleton.NewJSONArrayStream(w)
+//	defer stream.Close()
+//	for rows.Next() {
+//	    ...
+//	    if err := stream.Write(row); err != nil {
+//	        return
+//	    }
+//	}
+//
+// ```
+type JSONArrayStream struct {
+	w       http.ResponseWriter
+	started bool
+	closed  bool
+	count   int
+	err     error
+}
+
+// NewJSONArrayStream creates a stream writing to the provided response.
+func NewJSONArrayStream(w http.ResponseWriter) *JSONArrayStream {
+	return &JSONArrayStream{w: w}
+}
+
+// start writes the header and the opening bracket.
+func (s *JSONArrayStream) start() {
+	s.started = true
+	s.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	s.w.WriteHeader(http.StatusOK)
+	s.write([]byte("["))
+}
+
+func (s *JSONArrayStream) write(b []byte) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = s.w.Write(b)
+}
+
+// Write encodes item and writes it to the response as the next element of
+// the array.
+func (s *JSONArrayStream) Write(item interface{}) error {
+	if s.closed {
+		return ErrJSONStreamClosed
+	}
+	if s.err != nil {
+		return s.err
+	}
+
+	b, err := DefaultJSONCodec.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	if !s.started {
+		s.start()
+	}
+	if s.count > 0 {
+		s.write([]byte(","))
+	}
+	s.write(b)
+	s.count++
+
+	if f, ok := s.w.(http.Flusher); ok && s.err == nil {
+		f.Flush()
+	}
+	return s.err
+}
+
+// Count returns the number of items written.
+func (s *JSONArrayStream) Count() int {
+	return s.count
+}
+
+// Close terminates the array. If no items were written, an empty array is
+// written. Calling Close more than once has no effect.
+func (s *JSONArrayStream) Close() error {
+	if s.closed {
+		return s.err
+	}
+	s.closed = true
+	if !s.started {
+		s.start()
+	}
+	s.write([]byte("]"))
+	return s.err
+}
@@ -0,0 +1,77 @@
+package skeleton
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// JSONCodec encodes and decodes JSON for the response helpers (WriteJSON,
+// DecodeBody) and for Bind. The standard library is used by default. Faster
+// libraries can be used by replacing DefaultJSONCodec; for example,
+// github.com/json-iterator/go satisfies this interface directly:
+//
+// ```
+//
+//	skeleton.DefaultJSONCodec = jsoniter.ConfigCompatibleWithStandardLibrary
+//
+// ```
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdJSONCodec implements JSONCodec using encoding/json.
+type StdJSONCodec struct{}
+
+func (StdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (StdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// DefaultJSONCodec is the codec used by this package. It should only be
+// replaced during initialization, before any requests are served.
+var DefaultJSONCodec JSONCodec = StdJSONCodec{}
+
+// WriteJSON writes v as a JSON response with the provided status code.
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) error {
+	b, err := DefaultJSONCodec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(b)
+	return err
+}
+
+// DecodeBody decodes the JSON body of the request into v. A body which cannot
+// be read or decoded results in a ValidationErrors, which maps to a 400
+// through ErrorStatus. Read errors which carry their own status (e.g., a 413
+// from Decompress) are returned unchanged.
+func DecodeBody(r *http.Request, v interface{}) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ValidationErrors{{Message: "request body is empty"}}
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		// Errors carrying a status (e.g., a body which is too large) are
+		// returned as is.
+		var sc StatusCoder
+		if errors.As(err, &sc) {
+			return err
+		}
+		return ValidationErrors{{Message: "could not read body: " + err.Error()}}
+	}
+	if len(b) == 0 {
+		return ValidationErrors{{Message: "request body is empty"}}
+	}
+	if err := DefaultJSONCodec.Unmarshal(b, v); err != nil {
+		return ValidationErrors{{Message: "invalid JSON body: " + err.Error()}}
+	}
+	return nil
+}
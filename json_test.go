@@ -0,0 +1,74 @@
+package skeleton
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+type benchmarkPayload struct {
+	ID      int               `json:"id"`
+	Name    string            `json:"name"`
+	Email   string            `json:"email"`
+	Active  bool              `json:"active"`
+	Tags    []string          `json:"tags"`
+	Score   float64           `json:"score"`
+	Profile map[string]string `json:"profile"`
+}
+
+var benchmarkCodecs = []struct {
+	name  string
+	codec JSONCodec
+}{
+	{"std", StdJSONCodec{}},
+	{"jsoniter", jsoniter.ConfigCompatibleWithStandardLibrary},
+}
+
+func newBenchmarkPayload() []benchmarkPayload {
+	payload := make([]benchmarkPayload, 100)
+	for i := range payload {
+		payload[i] = benchmarkPayload{
+			ID:      i,
+			Name:    "User Name",
+			Email:   "user@example.com",
+			Active:  i%2 == 0,
+			Tags:    []string{"admin", "beta", "staff"},
+			Score:   float64(i) * 1.5,
+			Profile: map[string]string{"country": "CA", "language": "en"},
+		}
+	}
+	return payload
+}
+
+func BenchmarkJSONCodecMarshal(b *testing.B) {
+	payload := newBenchmarkPayload()
+	for _, c := range benchmarkCodecs {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.codec.Marshal(payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkJSONCodecUnmarshal(b *testing.B) {
+	data, err := StdJSONCodec{}.Marshal(newBenchmarkPayload())
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, c := range benchmarkCodecs {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				var v []benchmarkPayload
+				if err := c.codec.Unmarshal(data, &v); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// LifecycleHook is a service (e.g., a database, a cache or a logger) managed
+// by a Lifecycle. Either function may be nil.
+type LifecycleHook struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// LifecycleError is returned by Lifecycle when one or more hooks fail.
+// Failed holds the names of the failing hooks in the order they were run.
+type LifecycleError struct {
+	Phase  string
+	Failed []string
+	errs   []error
+}
+
+func (e *LifecycleError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for i, name := range e.Failed {
+		msgs = append(msgs, name+": "+e.errs[i].Error())
+	}
+	return e.Phase + " failed: " + strings.Join(msgs, ", ")
+}
+
+// Unwrap returns the errors returned by the failing hooks.
+func (e *LifecycleError) Unwrap() []error {
+	return e.errs
+}
+
+func (e *LifecycleError) add(name string, err error) {
+	e.Failed = append(e.Failed, name)
+	e.errs = append(e.errs, err)
+}
+
+// Lifecycle starts services in the order they were appended, and stops them
+// in reverse order. This formalizes the initialization of services before
+// the server is run, and their cleanup once it has shut down.
+//
+// ```
+//
+//	lc := &skeleton.Lifecycle{}
+//	lc.Append(skeleton.LifecycleHook{Name: "db", Start: db.Connect, Stop: db.Close})
+//	lc.Append(skeleton.LifecycleHook{Name: "cache", Start: cache.Connect})
+//	if err := lc.Run(server, &CustomDelegate{}); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// ```
+type Lifecycle struct {
+	hooks   []LifecycleHook
+	started int
+}
+
+// Append registers a hook. Hooks should be appended before Start is called.
+func (l *Lifecycle) Append(hooks ...LifecycleHook) {
+	l.hooks = append(l.hooks, hooks...)
+}
+
+// Start runs the Start function of each hook in order. If a hook fails, the
+// hooks which were already started are stopped in reverse order, and a
+// *LifecycleError describing the failures is returned.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	for _, h := range l.hooks[l.started:] {
+		if h.Start != nil {
+			if err := h.Start(ctx); err != nil {
+				lerr := &LifecycleError{Phase: "start"}
+				lerr.add(h.Name, err)
+				l.stop(ctx, lerr)
+				return lerr
+			}
+		}
+		l.started++
+	}
+	return nil
+}
+
+// Stop runs the Stop function of every started hook in reverse order. All
+// hooks are stopped even if some fail; the failures are reported in a
+// *LifecycleError.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	lerr := &LifecycleError{Phase: "stop"}
+	l.stop(ctx, lerr)
+	if len(lerr.Failed) > 0 {
+		return lerr
+	}
+	return nil
+}
+
+// stop stops the started hooks in reverse order, adding failures to lerr.
+func (l *Lifecycle) stop(ctx context.Context, lerr *LifecycleError) {
+	for ; l.started > 0; l.started-- {
+		h := l.hooks[l.started-1]
+		if h.Stop == nil {
+			continue
+		}
+		if err := h.Stop(ctx); err != nil {
+			lerr.add(h.Name, err)
+		}
+	}
+}
+
+// Run starts the hooks, runs the runner using Run, and stops the hooks once
+// the runner has shut down. If starting fails, the runner is not run. The
+// hooks are stopped even if Run returns an error. The errors of Run and Stop
+// are both returned, joined with errors.Join.
+func (l *Lifecycle) Run(runner Runner, runDelegate RunDelegate) error {
+	if err := l.Start(context.Background()); err != nil {
+		return err
+	}
+	runErr := Run(runner, runDelegate)
+	return errors.Join(runErr, l.Stop(context.Background()))
+}
@@ -0,0 +1,50 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// failingRunner is a Runner whose Run fails immediately.
+type failingRunner struct {
+	err error
+}
+
+func (r *failingRunner) Run(onShutdown ...func()) error {
+	return r.err
+}
+
+func (r *failingRunner) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestLifecycleRunJoinsErrors(t *testing.T) {
+	errRun := errors.New("address already in use")
+	errStop := errors.New("could not flush")
+
+	var stopped bool
+	lc := &Lifecycle{}
+	lc.Append(LifecycleHook{
+		Name: "cache",
+		Stop: func(context.Context) error {
+			stopped = true
+			return errStop
+		},
+	})
+
+	err := lc.Run(&failingRunner{err: errRun}, nil)
+	if !stopped {
+		t.Error("the hook was not stopped after the runner failed")
+	}
+	if !errors.Is(err, errRun) {
+		t.Errorf("error = %v, want it to include the error of the runner", err)
+	}
+	if !errors.Is(err, errStop) {
+		t.Errorf("error = %v, want it to include the error of the hook", err)
+	}
+	var lerr *LifecycleError
+	if !errors.As(err, &lerr) || lerr.Phase != "stop" {
+		t.Errorf("error = %v, want it to include a stop *LifecycleError", err)
+	}
+}
@@ -0,0 +1,90 @@
+package skeleton
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// DefaultPriority is the priority of routes which do not declare one through
+// RouteMetadata.Priority.
+const DefaultPriority = 5
+
+var (
+	ErrLoadShed = errors.New("request shed due to load")
+)
+
+// ShedThreshold rejects requests with a priority below MinPriority once the
+// utilization of the server (in-flight requests over capacity) reaches
+// Utilization.
+type ShedThreshold struct {
+	Utilization float64
+	MinPriority int
+}
+
+// DefaultShedThresholds are used by a LoadShedder without thresholds. Once
+// 80% full, requests with a priority below 5 are rejected; once 95% full,
+// only requests with a priority of 8 or more are admitted.
+var DefaultShedThresholds = []ShedThreshold{
+	{Utilization: 0.8, MinPriority: DefaultPriority},
+	{Utilization: 0.95, MinPriority: 8},
+}
+
+// LoadShedder limits the number of requests processed at once across the
+// server and, as it nears capacity, sheds low-priority requests (e.g.,
+// background sync or analytics) so that high-priority ones (e.g., checkout
+// or login) remain available. Routes declare their priority through
+// RouteMetadata.Priority; higher is more important. Shed requests receive a
+// 503 with a Retry-After header.
+type LoadShedder struct {
+	Limiter *ConcurrencyLimiter
+
+	// Thresholds determine which priorities are rejected at which
+	// utilization. Defaults to DefaultShedThresholds.
+	Thresholds []ShedThreshold
+}
+
+// NewLoadShedder creates a LoadShedder admitting at most capacity concurrent
+// requests, using DefaultShedThresholds. A capacity below 1 is treated as 1;
+// see NewConcurrencyLimiter.
+func NewLoadShedder(capacity int) *LoadShedder {
+	return &LoadShedder{Limiter: NewConcurrencyLimiter(capacity)}
+}
+
+// minPriority returns the minimum priority admitted at the current
+// utilization.
+func (l *LoadShedder) minPriority() int {
+	thresholds := l.Thresholds
+	if thresholds == nil {
+		thresholds = DefaultShedThresholds
+	}
+	utilization := float64(l.Limiter.InFlight()) / float64(l.Limiter.Capacity())
+
+	min := 0
+	for _, t := range thresholds {
+		if utilization >= t.Utilization && t.MinPriority > min {
+			min = t.MinPriority
+		}
+	}
+	return min
+}
+
+// admit admits a request with the provided priority. If the request is shed,
+// Retry-After is set on the response and a 503 *HttpError is returned.
+// Otherwise, the returned function must be called once the request is done.
+func (l *LoadShedder) admit(w http.ResponseWriter, priority int) (func(), error) {
+	if priority >= l.minPriority() && l.Limiter.TryAcquire() {
+		return l.Limiter.Release, nil
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(DefaultRetryAfter))
+	return nil, NewHttpError(http.StatusServiceUnavailable, ErrLoadShed.Error(), ErrLoadShed)
+}
+
+// routePriority returns the priority declared in the metadata, or
+// DefaultPriority.
+func routePriority(meta RouteMetadata) int {
+	if meta.Priority == 0 {
+		return DefaultPriority
+	}
+	return meta.Priority
+}
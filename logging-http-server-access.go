@@ -0,0 +1,59 @@
+package skeleton
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/monstercat/golib/logger"
+)
+
+// logAccess emits the single-line access log entry of a request, describing
+// its full outcome. pattern is the pattern of the matched route, if any.
+func (s *LoggingHttpServer[Ctx, R]) logAccess(
+	r *http.Request,
+	cw *statusCapturingResponseWriter,
+	requestId string,
+	pattern string,
+	start time.Time,
+	err error,
+	panicked bool,
+) {
+	status := cw.Status()
+	switch {
+	case panicked && !cw.Written():
+		status = http.StatusInternalServerError
+	case status == 0:
+		// net/http writes a 200 if the handler wrote nothing.
+		status = http.StatusOK
+	}
+
+	entry := map[string]interface{}{
+		"Message":   "Request completed",
+		"ID":        requestId,
+		"Method":    r.Method,
+		"Path":      r.URL.Path,
+		"Route":     pattern,
+		"Status":    status,
+		"LatencyMs": float64(time.Since(start).Microseconds()) / 1000,
+		"Bytes":     cw.BytesWritten(),
+		"ClientIP":  ClientIP(r, s.TrustedProxies),
+	}
+	if err != nil {
+		entry["Error"] = err.Error()
+	}
+	if panicked {
+		entry["Panic"] = true
+	}
+	if id := CorrelationID(r.Context()); id != "" {
+		entry["CorrelationID"] = id
+	}
+
+	severity := logger.SeverityInfo
+	switch {
+	case status >= 500 || panicked:
+		severity = logger.SeverityError
+	case status >= 400:
+		severity = logger.SeverityWarning
+	}
+	s.Logger.Log(severity, entry)
+}
@@ -0,0 +1,76 @@
+package skeleton
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/monstercat/golib/logger"
+)
+
+// AllocationGuard is an opt-in diagnostic for the LoggingHttpServer. It
+// samples heap allocations around the handling of a request and logs a
+// warning, including the route pattern, when a single request allocates more
+// than Threshold bytes. It does not enforce anything; the request is served
+// as usual.
+//
+// Allocations are measured using runtime.ReadMemStats, which briefly stops
+// the world. To limit the cost, only one in every SampleRate requests is
+// measured. Note that the memory statistics are process-wide, so allocations
+// performed by concurrent requests are included in the measurement. As a
+// result, the reported numbers should be treated as an upper bound which is
+// most accurate under low concurrency.
+type AllocationGuard struct {
+	// Threshold is the number of bytes a single request needs to allocate
+	// before a warning is logged.
+	Threshold uint64
+
+	// SampleRate determines how often requests are measured. One in every
+	// SampleRate requests is measured. A value of 0 or 1 measures every
+	// request.
+	SampleRate uint64
+
+	counter uint64
+}
+
+// allocationSample holds the allocation count at the start of a sampled
+// request.
+type allocationSample struct {
+	sampled    bool
+	totalAlloc uint64
+}
+
+// start begins a measurement if the current request should be sampled. It is
+// safe to call on a nil AllocationGuard.
+func (g *AllocationGuard) start() allocationSample {
+	if g == nil {
+		return allocationSample{}
+	}
+	n := atomic.AddUint64(&g.counter, 1)
+	if g.SampleRate > 1 && n%g.SampleRate != 0 {
+		return allocationSample{}
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return allocationSample{sampled: true, totalAlloc: m.TotalAlloc}
+}
+
+// finish completes the measurement and logs a warning through the provided
+// logger if the threshold has been exceeded.
+func (g *AllocationGuard) finish(l logger.Logger, sample allocationSample, pattern string) {
+	if g == nil || !sample.sampled {
+		return
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	allocated := m.TotalAlloc - sample.totalAlloc
+	if allocated < g.Threshold {
+		return
+	}
+	l.Log(logger.SeverityWarning, map[string]interface{}{
+		"Message":   "Request allocation exceeded threshold",
+		"Route":     pattern,
+		"Allocated": allocated,
+		"Threshold": g.Threshold,
+	})
+}
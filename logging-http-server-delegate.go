@@ -3,6 +3,7 @@ package skeleton
 import (
 	"net/http"
 
+	"cloud.google.com/go/logging"
 	"github.com/monstercat/golib/logger"
 )
 
@@ -19,13 +20,83 @@ type LoggingHttpServerDelegate[Ctx any, R Route[Ctx]] interface {
 	Generate(http.ResponseWriter, *http.Request, R, Session, logger.Logger, logger.HTTPRequest) Ctx
 }
 
+// LoggingHttpServerDelegateFuncs implements LoggingHttpServerDelegate based
+// on the provided functions, analogous to HttpServerDelegateFunc. Use
+// NewLoggingHttpServerDelegateFuncs to create it.
+type LoggingHttpServerDelegateFuncs[Ctx any, R Route[Ctx]] struct {
+	// RequestLoggerFunc generates the request logger. If nil, a request
+	// logger for Google Cloud Logging is generated, as done by
+	// LoggingGoHttpServerDelegate.
+	RequestLoggerFunc func(l logger.Logger, r *http.Request) logger.HTTPRequest
+
+	// GenerateFunc generates the context to pass into the routes.
+	GenerateFunc func(http.ResponseWriter, *http.Request, R, Session, logger.Logger, logger.HTTPRequest) Ctx
+}
+
+// NewLoggingHttpServerDelegateFuncs creates a LoggingHttpServerDelegate from
+// the provided functions. requestLogger can be nil to use the default
+// request logger.
+//
+// ```
+//
+//	del := skeleton.NewLoggingHttpServerDelegateFuncs(nil, func(
+//	    w http.ResponseWriter, r *http.Request, route *skeleton.GoRouterRoute[*Context],
+//	    s skeleton.Session, l logger.Logger, lr logger.HTTPRequest,
+//	) *Context {
+//	    return &Context{W: w, R: r, Logger: l}
+//	})
+//
+// ```
+func NewLoggingHttpServerDelegateFuncs[Ctx any, R Route[Ctx]](
+	requestLogger func(l logger.Logger, r *http.Request) logger.HTTPRequest,
+	generate func(http.ResponseWriter, *http.Request, R, Session, logger.Logger, logger.HTTPRequest) Ctx,
+) *LoggingHttpServerDelegateFuncs[Ctx, R] {
+	return &LoggingHttpServerDelegateFuncs[Ctx, R]{
+		RequestLoggerFunc: requestLogger,
+		GenerateFunc:      generate,
+	}
+}
+
+// RequestLogger generates a logger specific to the HTTP request.
+func (d *LoggingHttpServerDelegateFuncs[Ctx, R]) RequestLogger(l logger.Logger, r *http.Request) logger.HTTPRequest {
+	if d.RequestLoggerFunc == nil {
+		return googleRequestLogger(l, r)
+	}
+	return d.RequestLoggerFunc(l, r)
+}
+
+// Generate generates a context to pass into the routes.
+func (d *LoggingHttpServerDelegateFuncs[Ctx, R]) Generate(w http.ResponseWriter, req *http.Request, r R, s Session, l logger.Logger, lr logger.HTTPRequest) Ctx {
+	return d.GenerateFunc(w, req, r, s, l, lr)
+}
+
+// googleRequestLogger generates a request logger for Google Cloud Logging.
+func googleRequestLogger(l logger.Logger, r *http.Request) logger.HTTPRequest {
+	return &logger.GoogleHTTPRequest{
+		Logger:     l,
+		LogRequest: &logging.HTTPRequest{Request: r},
+	}
+}
+
 // HttpServerDelegateBridge bridges between an HttpServerDelegate and a
 // LoggingServerDelegate. It implements HttpServerDelegate, and requires a
 // LoggingHttpServerDelegate.
+//
+// The bridge also records the route which was matched, so that the
+// LoggingHttpServer has access to it once the request has been served.
 type HttpServerDelegateBridge[Ctx any, R Route[Ctx]] struct {
 	Logger        logger.Logger
 	RequestLogger logger.HTTPRequest
 	Delegate      LoggingHttpServerDelegate[Ctx, R]
+
+	// Route is the route which was matched. It is only valid if Matched is
+	// true.
+	Route   R
+	Matched bool
+
+	// Services is the set of registered service tags. If the matched route
+	// declares one, it is added to the logger passed to the delegate.
+	Services []string
 }
 
 func NewHttpServerDelegateBridge[Ctx any, R Route[Ctx]](l logger.Logger, req logger.HTTPRequest, del LoggingHttpServerDelegate[Ctx, R]) *HttpServerDelegateBridge[Ctx, R] {
@@ -37,5 +108,37 @@ func NewHttpServerDelegateBridge[Ctx any, R Route[Ctx]](l logger.Logger, req log
 }
 
 func (b *HttpServerDelegateBridge[Ctx, R]) Generate(wr http.ResponseWriter, req *http.Request, r R, sess Session) Ctx {
-	return b.Delegate.Generate(wr, req, r, sess, b.Logger, b.RequestLogger)
+	b.Route = r
+	b.Matched = true
+
+	l := routeLogger(b.Logger, r)
+	if service := serviceTag(r, b.Services); service != "" {
+		l = &logger.Contextual{
+			Context: logger.NewContext("Service", map[string]interface{}{
+				"Name": service,
+			}),
+			Logger: l,
+		}
+	}
+	return b.Delegate.Generate(wr, req, r, sess, l, b.RequestLogger)
+}
+
+// routeLogger adds the pattern and path parameters of the matched route, if
+// it reports them, to the context of the logger. Logging the pattern rather
+// than the path keeps the cardinality of logged values low.
+func routeLogger(l logger.Logger, route any) logger.Logger {
+	fields := map[string]interface{}{}
+	if pattern := routePattern(route); pattern != "" {
+		fields["Pattern"] = pattern
+	}
+	if pr, ok := route.(ParamsRoute); ok && len(pr.PathParams()) > 0 {
+		fields["Params"] = pr.PathParams()
+	}
+	if len(fields) == 0 {
+		return l
+	}
+	return &logger.Contextual{
+		Context: logger.NewContext("Route", fields),
+		Logger:  l,
+	}
 }
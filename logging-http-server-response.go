@@ -0,0 +1,39 @@
+package skeleton
+
+import (
+	"net/http"
+
+	"github.com/monstercat/golib/logger"
+)
+
+// ResponseRecordingLogger is an optional interface for request loggers
+// (logger.HTTPRequest) which record the response of the request, so that it
+// is part of the entries they log.
+type ResponseRecordingLogger interface {
+	RecordResponse(status int, bytes int64)
+}
+
+// recordResponse feeds the status and size of the response, as captured by
+// cw, to the request logger. A response without an explicit status is
+// recorded as a 200, which is what net/http sends.
+func recordResponse(reqLogger logger.HTTPRequest, cw *statusCapturingResponseWriter) {
+	status := cw.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	recordResponseTo(reqLogger, status, cw.BytesWritten())
+}
+
+// recordResponseTo records the response in the request logger, if it
+// supports it.
+func recordResponseTo(reqLogger logger.HTTPRequest, status int, bytes int64) {
+	switch l := reqLogger.(type) {
+	case ResponseRecordingLogger:
+		l.RecordResponse(status, bytes)
+	case *logger.GoogleHTTPRequest:
+		if l.LogRequest != nil {
+			l.LogRequest.Status = status
+			l.LogRequest.ResponseSize = bytes
+		}
+	}
+}
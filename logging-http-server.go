@@ -1,7 +1,9 @@
 package skeleton
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/monstercat/golib/logger"
@@ -20,6 +22,57 @@ type LoggingHttpServer[Ctx any, R Route[Ctx]] struct {
 	//
 	// The Request Logger needs to be of type logger.HTTPRequest
 	Delegate LoggingHttpServerDelegate[Ctx, R]
+
+	// AllocationGuard, if provided, samples the allocations of requests and
+	// logs those which allocate beyond its threshold. See AllocationGuard for
+	// the overhead involved.
+	AllocationGuard *AllocationGuard
+
+	// WarnDuplicateWriteHeader logs a warning when WriteHeader is called more
+	// than once for a request. Only the first status is ever written.
+	WarnDuplicateWriteHeader bool
+
+	// SingleLineAccessLog emits a single structured entry per request once
+	// it has completed (including 404s, errors and panics), containing the
+	// method, route pattern, status, latency, size, client IP and request
+	// ID. Warnings for client errors (e.g., 404) are folded into the entry
+	// instead of being logged separately.
+	SingleLineAccessLog bool
+
+	// RequestIDHeader is the header in which the request ID is echoed (e.g.,
+	// X-Request-ID). Defaults to DefaultRequestIDHeader.
+	RequestIDHeader string
+
+	// RequestIDFunc returns the ID of a request, which is added to its logs
+	// and echoed in RequestIDHeader. By default, a valid ID received in
+	// RequestIDHeader (e.g., from an upstream proxy) is reused, and a UUID is
+	// generated otherwise. As the incoming ID is supplied by the client,
+	// provide a function which always generates one if the server is not
+	// behind a proxy which sets it.
+	RequestIDFunc func(r *http.Request) string
+}
+
+// DefaultRequestIDHeader is the header in which the request ID is echoed when
+// LoggingHttpServer.RequestIDHeader is not set.
+const DefaultRequestIDHeader = "request-id"
+
+// requestIDHeader returns the header carrying the request ID.
+func (s *LoggingHttpServer[Ctx, R]) requestIDHeader() string {
+	if s.RequestIDHeader != "" {
+		return s.RequestIDHeader
+	}
+	return DefaultRequestIDHeader
+}
+
+// requestID returns the ID of the request.
+func (s *LoggingHttpServer[Ctx, R]) requestID(r *http.Request) string {
+	if s.RequestIDFunc != nil {
+		return s.RequestIDFunc(r)
+	}
+	if id := r.Header.Get(s.requestIDHeader()); validCorrelationID(id) {
+		return id
+	}
+	return uuid.New().String()
 }
 
 // NewLoggingHttpServer creates a new HTTP server with logging capability.
@@ -52,25 +105,45 @@ func NewLoggingHttpServer[Ctx any, R Route[Ctx]](
 	return server
 }
 
-// Run the server. This is a blocking function.
+// Run the server. This is a blocking function. An error is returned if Addr
+// is malformed; see NormalizeAddr for the accepted forms. The Listener is
+// served instead of Addr if provided. The server is served over TLS if
+// TLSConfig is provided.
 func (s *LoggingHttpServer[Ctx, R]) Run(onShutdown ...func()) error {
-	defer s.S.Shutdown()
+	if s.S != nil {
+		defer s.S.Shutdown()
+	}
 
 	// This is so that we can handle cleanup
-	s.Server = &http.Server{
-		Addr:    s.Addr,
-		Handler: s,
+	server, err := newBaseServer(s.Addr, s, onShutdown...)
+	if err != nil {
+		return err
 	}
-	for _, f := range onShutdown {
-		s.Server.RegisterOnShutdown(f)
-	}
-	return s.Server.ListenAndServe()
+	s.Timeouts.apply(server)
+	s.IdleTimeouts.apply(server)
+	s.Server = server
+	return s.listenAndServe(server)
 }
 
 // ServeHTTP allows LoggingHttpServer to implement the http.Handler interface.
 func (s *LoggingHttpServer[Ctx, R]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	requestId := uuid.New().String() // Request ID (unique to the current request)
-	w.Header().Set("request-id", requestId)
+	_, _ = s.ServeAndRender(w, r)
+}
+
+// ServeAndRender serves and logs the request as ServeHTTP does, but also
+// returns the status which was written and the error returned by Serve. See
+// HttpServer.ServeAndRender.
+func (s *LoggingHttpServer[Ctx, R]) ServeAndRender(w http.ResponseWriter, r *http.Request) (int, error) {
+	cw := s.captureResponse(w, r)
+	w = cw
+
+	if s.Metrics != nil {
+		done := s.Metrics.begin()
+		defer func() { done(r, cw) }()
+	}
+
+	requestId := s.requestID(r) // Request ID (unique to the current request)
+	w.Header().Set(s.requestIDHeader(), requestId)
 
 	// Generate the request logger.
 	reqLogger := s.Delegate.RequestLogger(s, r)
@@ -80,29 +153,93 @@ func (s *LoggingHttpServer[Ctx, R]) ServeHTTP(w http.ResponseWriter, r *http.Req
 
 	// generate a logger for this specific request. This would be a
 	// contextual logger wrapping an HTTP logger.
+	fields := map[string]interface{}{
+		"ID":     requestId,
+		"Method": r.Method,
+		"Path":   r.URL.Path,
+	}
+	headerLogFields(r, s.HeaderExtractors, fields)
+	if s.Correlation {
+		r = correlate(w, r)
+		fields["CorrelationID"] = CorrelationID(r.Context())
+	}
 	lgr := &logger.Contextual{
-		Context: logger.NewContext("Request", map[string]interface{}{
-			"ID":     requestId,
-			"Method": r.Method,
-			"Path":   r.URL.Path,
-		}),
-		Logger: reqLogger,
+		Context: logger.NewContext("Request", fields),
+		Logger:  reqLogger,
 	}
 
 	// Serve based on the route. We need to pass in a special delegate (since
 	// the HttpServer's delegate is nil.
-	err := s.HttpServer.ServeWithDelegate(w, r, NewHttpServerDelegateBridge[Ctx, R](lgr, reqLogger, s.Delegate))
+	bridge := NewHttpServerDelegateBridge[Ctx, R](lgr, reqLogger, s.Delegate)
+	bridge.Services = s.Services
+
+	var err error
+	if s.SingleLineAccessLog {
+		start := time.Now()
+		defer func() {
+			v := recover()
+			pattern := ""
+			if bridge.Matched {
+				pattern = routePattern(bridge.Route)
+			}
+			s.logAccess(r, cw, requestId, pattern, start, err, v != nil)
+			if v != nil {
+				panic(v)
+			}
+		}()
+	}
+
+	sample := s.AllocationGuard.start()
+	err = s.HttpServer.ServeWithDelegate(w, r, bridge)
+
+	// Record the response, so that the entries logged below carry it. It is
+	// recorded again once the error response (if any) has been written.
+	recordResponse(reqLogger, cw)
+	defer recordResponse(reqLogger, cw)
+
+	// Entries logged from here on describe the matched route, as those
+	// logged by the handler do.
+	var rl logger.Logger = lgr
+	if bridge.Matched {
+		rl = routeLogger(lgr, bridge.Route)
+		s.AllocationGuard.finish(rl, sample, routePattern(bridge.Route))
+	}
+	if cw.empty {
+		rl.Log(logger.SeverityWarning, "Handler returned without writing a response")
+	}
+	if s.WarnDuplicateWriteHeader && cw.DuplicateStatus() != 0 {
+		rl.Log(logger.SeverityWarning, map[string]interface{}{
+			"Message":   "WriteHeader called more than once",
+			"Status":    cw.Status(),
+			"Duplicate": cw.DuplicateStatus(),
+		})
+	}
+	if violations := cw.ResponseViolations(); len(violations) > 0 {
+		rl.Log(logger.SeverityWarning, map[string]interface{}{
+			"Message":    "Response does not match the documented schema",
+			"Route":      routePattern(bridge.Route),
+			"Status":     cw.Status(),
+			"Violations": violations,
+		})
+	}
 	if err == nil {
-		return
+		return writtenStatus(cw), nil
 	}
-	if err == ErrNoRoute {
-		lgr.Log(logger.SeverityWarning, "Could not find route")
-		w.WriteHeader(http.StatusNotFound)
-		return
+	if ErrorStatus(err) != http.StatusInternalServerError {
+		if !s.SingleLineAccessLog {
+			rl.Log(logger.SeverityWarning, err.Error())
+		}
+		s.writeError(w, r, err)
+		return writtenStatus(cw), err
 	}
 
-	w.WriteHeader(http.StatusInternalServerError)
-	w.Write([]byte("The system services are temporarily unavailable at the moment."))
-	reqLogger.Log(logger.SeverityError, err)
-	return
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		reqLogger.Log(logger.SeverityError, panicErr.LogFields())
+		abortIfPanicAfterWrite(cw, err)
+	} else {
+		reqLogger.Log(logger.SeverityError, err)
+	}
+	s.writeError(w, r, err)
+	return writtenStatus(cw), err
 }
@@ -0,0 +1,108 @@
+package skeleton
+
+import (
+	"github.com/monstercat/golib/logger"
+)
+
+// MultiLogger fans out log calls to several loggers (e.g., a logger.Standard
+// for local tailing and a Cloud Logging logger), so that it can be used as
+// the logger of the LoggingHttpServer. A sink which panics does not prevent
+// the remaining sinks from receiving the entry.
+type MultiLogger []logger.Logger
+
+// NewMultiLogger creates a MultiLogger from the provided loggers. Nil loggers
+// are ignored.
+func NewMultiLogger(loggers ...logger.Logger) MultiLogger {
+	m := make(MultiLogger, 0, len(loggers))
+	for _, l := range loggers {
+		if l != nil {
+			m = append(m, l)
+		}
+	}
+	return m
+}
+
+// Log logs the payload to every logger.
+func (m MultiLogger) Log(severity logger.Severity, payload interface{}) {
+	for _, l := range m {
+		logToSink(l, severity, payload)
+	}
+}
+
+// MultiHTTPRequest fans out log calls to several request loggers, for use
+// by LoggingHttpServerDelegate.RequestLogger. As with MultiLogger, a sink
+// which panics does not prevent the remaining sinks from being called.
+type MultiHTTPRequest struct {
+	logger.HTTPRequest
+	others []logger.HTTPRequest
+}
+
+// NewMultiHTTPRequest creates a MultiHTTPRequest from the provided request
+// loggers. At least one request logger needs to be provided.
+func NewMultiHTTPRequest(primary logger.HTTPRequest, others ...logger.HTTPRequest) *MultiHTTPRequest {
+	return &MultiHTTPRequest{
+		HTTPRequest: primary,
+		others:      others,
+	}
+}
+
+// Log logs the payload to every request logger.
+func (m *MultiHTTPRequest) Log(severity logger.Severity, payload interface{}) {
+	logToSink(m.HTTPRequest, severity, payload)
+	for _, l := range m.others {
+		logToSink(l, severity, payload)
+	}
+}
+
+// SetStatus sets the status in every request logger.
+func (m *MultiHTTPRequest) SetStatus(status int) {
+	m.each(func(l logger.HTTPRequest) { l.SetStatus(status) })
+}
+
+// SetCached sets whether the response was cached in every request logger.
+func (m *MultiHTTPRequest) SetCached(cached bool) {
+	m.each(func(l logger.HTTPRequest) { l.SetCached(cached) })
+}
+
+// StartTimer starts the timer of every request logger.
+func (m *MultiHTTPRequest) StartTimer() {
+	m.each(func(l logger.HTTPRequest) { l.StartTimer() })
+}
+
+// SetLatency sets the latency in every request logger.
+func (m *MultiHTTPRequest) SetLatency() {
+	m.each(func(l logger.HTTPRequest) { l.SetLatency() })
+}
+
+// each calls fn with every request logger, recovering from any panic so that
+// the remaining request loggers are still called.
+func (m *MultiHTTPRequest) each(fn func(l logger.HTTPRequest)) {
+	call := func(l logger.HTTPRequest) {
+		defer func() {
+			_ = recover()
+		}()
+		fn(l)
+	}
+	call(m.HTTPRequest)
+	for _, l := range m.others {
+		call(l)
+	}
+}
+
+// logToSink logs to a single sink, recovering from any panic so that the
+// remaining sinks still receive the entry.
+func logToSink(l logger.Logger, severity logger.Severity, payload interface{}) {
+	defer func() {
+		_ = recover()
+	}()
+	l.Log(severity, payload)
+}
+
+// RecordResponse records the response in every request logger. It
+// implements ResponseRecordingLogger.
+func (m *MultiHTTPRequest) RecordResponse(status int, bytes int64) {
+	recordResponseTo(m.HTTPRequest, status, bytes)
+	for _, l := range m.others {
+		recordResponseTo(l, status, bytes)
+	}
+}
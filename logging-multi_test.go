@@ -0,0 +1,64 @@
+package skeleton
+
+import (
+	"testing"
+
+	"github.com/monstercat/golib/logger"
+)
+
+// recordingHTTPRequest is a logger.HTTPRequest which records the calls made
+// to it.
+type recordingHTTPRequest struct {
+	status  int
+	cached  bool
+	timer   bool
+	latency bool
+	panics  bool
+}
+
+func (l *recordingHTTPRequest) Log(severity logger.Severity, payload interface{}) {}
+
+func (l *recordingHTTPRequest) SetStatus(status int) {
+	if l.panics {
+		panic("sink failed")
+	}
+	l.status = status
+}
+
+func (l *recordingHTTPRequest) SetCached(cached bool) {
+	if l.panics {
+		panic("sink failed")
+	}
+	l.cached = cached
+}
+
+func (l *recordingHTTPRequest) StartTimer() {
+	if l.panics {
+		panic("sink failed")
+	}
+	l.timer = true
+}
+
+func (l *recordingHTTPRequest) SetLatency() {
+	if l.panics {
+		panic("sink failed")
+	}
+	l.latency = true
+}
+
+func TestMultiHTTPRequestFansOut(t *testing.T) {
+	primary := &recordingHTTPRequest{panics: true}
+	others := []*recordingHTTPRequest{{}, {}}
+	m := NewMultiHTTPRequest(primary, others[0], others[1])
+
+	m.StartTimer()
+	m.SetStatus(404)
+	m.SetCached(true)
+	m.SetLatency()
+
+	for i, l := range others {
+		if !l.timer || l.status != 404 || !l.cached || !l.latency {
+			t.Errorf("sink %d = %+v, want every call recorded", i, *l)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package skeleton
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the buckets of
+// the latency histogram when RequestMetrics.Buckets is not set. They match
+// the default buckets of the Prometheus client.
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// UnmatchedRoute is the route label of requests which did not match a route.
+const UnmatchedRoute = "unmatched"
+
+// metricMethods are the methods reported as is. Other methods are reported
+// as "other", to bound the cardinality of the metrics.
+var metricMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// RequestMetrics records metrics of the requests served by an HttpServer
+// (see HttpServer.Metrics):
+//
+//   - http_requests_total, a counter by method, route, service and status;
+//   - http_requests_in_flight, a gauge of the requests being served;
+//   - http_request_duration_seconds, a histogram by method, route and
+//     service.
+//
+// Routes are labelled by their pattern (e.g., /users/:id) rather than the
+// path of the request, so that the number of series stays bounded. Requests
+// which do not match a route are labelled UnmatchedRoute.
+//
+// RequestMetrics is a prometheus.Collector, which is registered with the
+// registry of the application:
+//
+// ```
+//
+//	metrics := skeleton.NewRequestMetrics()
+//	prometheus.MustRegister(metrics)
+//	server.Metrics = metrics
+//
+// ```
+//
+// Alternatively, Handler exposes the metrics on their own. Either way, the
+// endpoint should be protected (e.g., with an AdminGuard).
+type RequestMetrics struct {
+	// Namespace, if set, prefixes the names of the metrics (e.g., myapp
+	// results in myapp_http_requests_total).
+	Namespace string
+
+	// Buckets are the upper bounds of the latency histogram, in seconds, in
+	// increasing order. Defaults to DefaultLatencyBuckets.
+	Buckets []float64
+
+	inFlight atomic.Int64
+
+	mu       sync.Mutex
+	requests map[requestMetricLabels]uint64
+	latency  map[requestMetricLabels]*latencyHistogram
+}
+
+type requestMetricLabels struct {
+	method  string
+	route   string
+	service string
+	status  int
+}
+
+type latencyHistogram struct {
+	counts []uint64 // Per bucket, not cumulative.
+	sum    float64
+	count  uint64
+}
+
+// NewRequestMetrics creates an empty RequestMetrics.
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{}
+}
+
+func (m *RequestMetrics) buckets() []float64 {
+	if len(m.Buckets) > 0 {
+		return m.Buckets
+	}
+	return DefaultLatencyBuckets
+}
+
+// begin records the start of a request. The returned function records its
+// completion, and must be called exactly once.
+func (m *RequestMetrics) begin() func(r *http.Request, cw *statusCapturingResponseWriter) {
+	start := time.Now()
+	m.inFlight.Add(1)
+	return func(r *http.Request, cw *statusCapturingResponseWriter) {
+		m.inFlight.Add(-1)
+		m.observe(r.Method, cw.pattern, cw.service, cw.Status(), time.Since(start))
+	}
+}
+
+func (m *RequestMetrics) observe(method, route, service string, status int, d time.Duration) {
+	if !metricMethods[method] {
+		method = "other"
+	}
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	if status == 0 {
+		// Nothing was written, which net/http sends as a 200.
+		status = http.StatusOK
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.requests == nil {
+		m.requests = make(map[requestMetricLabels]uint64)
+		m.latency = make(map[requestMetricLabels]*latencyHistogram)
+	}
+	m.requests[requestMetricLabels{method: method, route: route, service: service, status: status}]++
+
+	key := requestMetricLabels{method: method, route: route, service: service}
+	h, ok := m.latency[key]
+	if !ok {
+		h = &latencyHistogram{counts: make([]uint64, len(m.buckets()))}
+		m.latency[key] = h
+	}
+	seconds := d.Seconds()
+	for i, le := range m.buckets() {
+		if seconds <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+var (
+	_ prometheus.Collector = &RequestMetrics{}
+)
+
+// descs returns the descriptions of the metrics, in the order in which they
+// are collected.
+func (m *RequestMetrics) descs() (requests, inFlight, latency *prometheus.Desc) {
+	requests = prometheus.NewDesc(
+		prometheus.BuildFQName(m.Namespace, "", "http_requests_total"),
+		"Total number of HTTP requests.",
+		[]string{"method", "route", "service", "status"}, nil)
+	inFlight = prometheus.NewDesc(
+		prometheus.BuildFQName(m.Namespace, "", "http_requests_in_flight"),
+		"Number of HTTP requests being served.",
+		nil, nil)
+	latency = prometheus.NewDesc(
+		prometheus.BuildFQName(m.Namespace, "", "http_request_duration_seconds"),
+		"Latency of HTTP requests.",
+		[]string{"method", "route", "service"}, nil)
+	return requests, inFlight, latency
+}
+
+// Describe sends the descriptions of the metrics. It implements
+// prometheus.Collector.
+func (m *RequestMetrics) Describe(ch chan<- *prometheus.Desc) {
+	requests, inFlight, latency := m.descs()
+	ch <- requests
+	ch <- inFlight
+	ch <- latency
+}
+
+// Collect sends the current value of the metrics. It implements
+// prometheus.Collector.
+func (m *RequestMetrics) Collect(ch chan<- prometheus.Metric) {
+	requests, inFlight, latency := m.descs()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, n := range m.requests {
+		ch <- prometheus.MustNewConstMetric(requests, prometheus.CounterValue, float64(n),
+			k.method, k.route, k.service, strconv.Itoa(k.status))
+	}
+
+	ch <- prometheus.MustNewConstMetric(inFlight, prometheus.GaugeValue, float64(m.inFlight.Load()))
+
+	for k, h := range m.latency {
+		buckets := make(map[float64]uint64, len(m.buckets()))
+		var cumulative uint64
+		for i, le := range m.buckets() {
+			cumulative += h.counts[i]
+			buckets[le] = cumulative
+		}
+		ch <- prometheus.MustNewConstHistogram(latency, h.count, h.sum, buckets,
+			k.method, k.route, k.service)
+	}
+}
+
+// Handler returns an http.Handler which exposes the metrics in the
+// Prometheus text format, for applications which do not otherwise use a
+// Prometheus registry. Applications which do should register the
+// RequestMetrics as a prometheus.Collector instead.
+func (m *RequestMetrics) Handler() http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m)
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,54 @@
+package skeleton
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRequestMetricsCollector(t *testing.T) {
+	m := NewRequestMetrics()
+	m.Namespace = "app"
+	m.observe("GET", "/users/:id", "", 200, 20*time.Millisecond)
+	m.observe("GET", "/users/:id", "", 200, 2*time.Second)
+	m.observe("PROPFIND", "", "", 404, time.Millisecond)
+
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(m); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+
+	counts := map[string]float64{}
+	var histogramCount uint64
+	for _, f := range families {
+		for _, metric := range f.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range metric.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			switch f.GetName() {
+			case "app_http_requests_total":
+				counts[labels["method"]+" "+labels["route"]+" "+labels["status"]] = metric.GetCounter().GetValue()
+			case "app_http_request_duration_seconds":
+				if labels["route"] == "/users/:id" {
+					histogramCount = metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+
+	if got := counts["GET /users/:id 200"]; got != 2 {
+		t.Errorf("GET /users/:id 200 = %v, want 2", got)
+	}
+	if got := counts["other "+UnmatchedRoute+" 404"]; got != 1 {
+		t.Errorf("unmatched requests = %v, want 1", got)
+	}
+	if histogramCount != 2 {
+		t.Errorf("histogram sample count = %d, want 2", histogramCount)
+	}
+}
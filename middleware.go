@@ -0,0 +1,50 @@
+package skeleton
+
+import "net/http"
+
+// Middleware wraps a route handler. It can run code before and after the
+// handler by calling next, or short-circuit the request by not calling it.
+type Middleware[Ctx any] func(next func(Ctx)) func(Ctx)
+
+// MiddlewareRoute is an optional interface for routes which carry their own
+// middleware (e.g., a guard on a single endpoint). It runs inside the
+// middleware registered with HttpServer.Use, in the order returned.
+type MiddlewareRoute[Ctx any] interface {
+	Middlewares() []Middleware[Ctx]
+}
+
+// Use registers middleware which runs around every route handler. Middleware
+// runs in registration order, the first registered being the outermost. Use
+// should be called before the server starts serving requests.
+func (s *HttpServer[Ctx, R]) Use(mw ...Middleware[Ctx]) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// applyMiddleware composes the registered middleware, and the middleware of
+// the route, around the handler.
+func (s *HttpServer[Ctx, R]) applyMiddleware(route any, handler func(Ctx)) func(Ctx) {
+	if mr, ok := routeAs[MiddlewareRoute[Ctx]](route); ok {
+		handler = composeMiddleware(handler, mr.Middlewares())
+	}
+	return composeMiddleware(handler, s.middleware)
+}
+
+// composeMiddleware wraps the handler in the middleware, the first being the
+// outermost.
+func composeMiddleware[Ctx any](handler func(Ctx), mw []Middleware[Ctx]) func(Ctx) {
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return handler
+}
+
+// WithMiddleware wraps the handler in standard net/http middleware (e.g.,
+// from gorilla/handlers), the first being the outermost. As HttpServer and
+// LoggingHttpServer implement http.Handler, they can be wrapped as a whole;
+// see HttpServer.HandlerMiddleware to have Run serve the wrapped handler.
+func WithMiddleware(h http.Handler, mw ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
@@ -0,0 +1,236 @@
+package skeleton
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RouteDoc documents a route for the OpenAPI document. Schemas are provided
+// as JSON Schema objects (e.g., map[string]interface{}{"type": "string"});
+// they are not inferred.
+type RouteDoc struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+
+	// Params documents parameters. Path parameters (e.g., :id) which are not
+	// documented are added automatically as required strings.
+	Params []ParamDoc
+
+	// RequestBody is the JSON schema of the request body, if any.
+	RequestBody map[string]interface{}
+
+	// Responses maps status codes to their documentation. If empty, a single
+	// 200 response is documented.
+	Responses map[int]ResponseDoc
+}
+
+// ParamDoc documents a parameter of a route.
+type ParamDoc struct {
+	Name string
+
+	// In is where the parameter is located: path, query, header or cookie.
+	In          string
+	Description string
+	Required    bool
+	Schema      map[string]interface{}
+}
+
+// ResponseDoc documents a response of a route.
+type ResponseDoc struct {
+	Description string
+
+	// Schema is the JSON schema of the response body, if any.
+	Schema map[string]interface{}
+}
+
+// OpenAPIInfo is the info object of the OpenAPI document.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPISpec builds an OpenAPI 3 document describing the provided routes,
+// based on their RouteMetadata.Doc. Routes without documentation are still
+// listed. If serverURL is not empty, it is used as the base URL of the API.
+func OpenAPISpec(routes []RouteInfo, info OpenAPIInfo, serverURL string) map[string]interface{} {
+	paths := map[string]interface{}{}
+	for _, ri := range routes {
+		p, pathParams := openAPIPath(ri.Path)
+		item, ok := paths[p].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[p] = item
+		}
+		meta, _ := routeMetadata(ri.Route)
+		item[strings.ToLower(ri.Method)] = openAPIOperation(meta.Doc, pathParams)
+	}
+
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    info,
+		"paths":   paths,
+	}
+	if serverURL != "" {
+		spec["servers"] = []map[string]string{{"url": serverURL}}
+	}
+	return spec
+}
+
+// openAPIPath converts a route pattern (e.g., /users/:id) to an OpenAPI path
+// (e.g., /users/{id}), returning the names of the path parameters.
+func openAPIPath(pattern string) (string, []string) {
+	segments := strings.Split(pattern, "/")
+	var params []string
+	for i, s := range segments {
+		switch {
+		case strings.HasPrefix(s, ":") && len(s) > 1:
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		case strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") && len(s) > 2:
+			params = append(params, s[1:len(s)-1])
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func openAPIOperation(doc *RouteDoc, pathParams []string) map[string]interface{} {
+	if doc == nil {
+		doc = &RouteDoc{}
+	}
+	op := map[string]interface{}{}
+	if doc.OperationID != "" {
+		op["operationId"] = doc.OperationID
+	}
+	if doc.Summary != "" {
+		op["summary"] = doc.Summary
+	}
+	if doc.Description != "" {
+		op["description"] = doc.Description
+	}
+	if len(doc.Tags) > 0 {
+		op["tags"] = doc.Tags
+	}
+	if doc.Deprecated {
+		op["deprecated"] = true
+	}
+
+	var params []map[string]interface{}
+	documented := map[string]bool{}
+	for _, p := range doc.Params {
+		if p.In == "path" {
+			documented[p.Name] = true
+		}
+		params = append(params, openAPIParam(p))
+	}
+	for _, name := range pathParams {
+		if !documented[name] {
+			params = append(params, openAPIParam(ParamDoc{Name: name, In: "path"}))
+		}
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if doc.RequestBody != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  openAPIJSONContent(doc.RequestBody),
+		}
+	}
+
+	responses := map[string]interface{}{}
+	statuses := make([]int, 0, len(doc.Responses))
+	for status := range doc.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		rd := doc.Responses[status]
+		desc := rd.Description
+		if desc == "" {
+			desc = http.StatusText(status)
+		}
+		res := map[string]interface{}{"description": desc}
+		if rd.Schema != nil {
+			res["content"] = openAPIJSONContent(rd.Schema)
+		}
+		responses[strconv.Itoa(status)] = res
+	}
+	if len(responses) == 0 {
+		responses["200"] = map[string]interface{}{"description": http.StatusText(http.StatusOK)}
+	}
+	op["responses"] = responses
+	return op
+}
+
+func openAPIParam(p ParamDoc) map[string]interface{} {
+	schema := p.Schema
+	if schema == nil {
+		schema = map[string]interface{}{"type": "string"}
+	}
+	param := map[string]interface{}{
+		"name":     p.Name,
+		"in":       p.In,
+		"required": p.Required || p.In == "path",
+		"schema":   schema,
+	}
+	if p.Description != "" {
+		param["description"] = p.Description
+	}
+	return param
+}
+
+func openAPIJSONContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// OpenAPIHandler serves the OpenAPI document of the routes of the router as
+// JSON (e.g., at /openapi.json). The document is built on each request, so
+// it stays in sync with the registered routes. The base URL of the API is
+// derived from the request using ExternalURL, so it is correct behind a
+// proxy.
+func OpenAPIHandler(lister RouteLister, info OpenAPIInfo, trusted TrustedProxies) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spec := OpenAPISpec(lister.Routes(), info, ExternalURL(r, trusted).String())
+		_ = WriteJSON(w, http.StatusOK, spec)
+	})
+}
+
+var swaggerUITemplate = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>{{.Title}}</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>
+		window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui"});
+	</script>
+</body>
+</html>
+`))
+
+// SwaggerUIHandler serves a Swagger UI page for the OpenAPI document at
+// specURL (e.g., /openapi.json). The assets of Swagger UI are loaded from
+// the unpkg CDN.
+func SwaggerUIHandler(title, specURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = swaggerUITemplate.Execute(w, map[string]string{
+			"Title":   title,
+			"SpecURL": specURL,
+		})
+	})
+}
@@ -0,0 +1,173 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrNoUpstreams = errors.New("proxy requires at least one upstream")
+)
+
+// ReverseProxy forwards requests to one of a list of equivalent upstreams.
+//
+// If HedgeDelay is set, idempotent requests (GET and HEAD) are hedged: when
+// an upstream has not responded within HedgeDelay, the request is also sent
+// to the next upstream, and the first response to arrive is used. The
+// remaining attempts are cancelled. This reduces tail latency against slow
+// backends, at the cost of additional load on them. Hedging is never applied
+// to other methods, as sending them more than once is not safe, nor to
+// requests with a body, which could only be read by one of the attempts.
+//
+// The Host header of the client is forwarded unchanged to every upstream, as
+// with httputil.NewSingleHostReverseProxy, whether or not the request is
+// hedged.
+//
+// Upstreams should only differ by scheme and host; the path of the first
+// upstream is used as the base path for all of them.
+type ReverseProxy struct {
+	Upstreams  []*url.URL
+	HedgeDelay time.Duration
+
+	// Transport is used to reach the upstreams. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	proxy *httputil.ReverseProxy
+}
+
+// NewReverseProxy creates a ReverseProxy to the provided upstreams.
+func NewReverseProxy(hedgeDelay time.Duration, upstreams ...*url.URL) (*ReverseProxy, error) {
+	if len(upstreams) == 0 {
+		return nil, ErrNoUpstreams
+	}
+	p := &ReverseProxy{
+		Upstreams:  upstreams,
+		HedgeDelay: hedgeDelay,
+	}
+	p.proxy = httputil.NewSingleHostReverseProxy(upstreams[0])
+	p.proxy.Transport = &hedgingTransport{p: p}
+	return p, nil
+}
+
+// ServeHTTP forwards the request.
+func (p *ReverseProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.proxy.ServeHTTP(w, r)
+}
+
+// ProxyRoute creates a route which forwards requests to the proxy.
+func ProxyRoute[Ctx any](method, path string, p *ReverseProxy, adapter ContextAdapter[Ctx]) Route[Ctx] {
+	return GoHandlerRoute(method, path, p, adapter)
+}
+
+// hedgingTransport performs the round trips of a ReverseProxy, hedging them
+// when allowed.
+type hedgingTransport struct {
+	p *ReverseProxy
+}
+
+func (t *hedgingTransport) base() http.RoundTripper {
+	if t.p.Transport != nil {
+		return t.p.Transport
+	}
+	return http.DefaultTransport
+}
+
+type hedgeResult struct {
+	resp *http.Response
+	err  error
+	i    int
+}
+
+func (t *hedgingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if !idempotent || hasBody || t.p.HedgeDelay <= 0 || len(t.p.Upstreams) < 2 {
+		return t.base().RoundTrip(req)
+	}
+
+	n := len(t.p.Upstreams)
+	results := make(chan hedgeResult, n)
+	cancels := make([]context.CancelFunc, n)
+
+	launched := 0
+	launch := func() {
+		i := launched
+		launched++
+
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels[i] = cancel
+		out := req.Clone(ctx)
+		out.URL.Scheme = t.p.Upstreams[i].Scheme
+		out.URL.Host = t.p.Upstreams[i].Host
+		go func() {
+			resp, err := t.base().RoundTrip(out)
+			results <- hedgeResult{resp: resp, err: err, i: i}
+		}()
+	}
+
+	launch()
+	pending := 1
+	timer := time.NewTimer(t.p.HedgeDelay)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				// Cancel the losers and discard their responses.
+				for j := 0; j < launched; j++ {
+					if j != res.i {
+						cancels[j]()
+					}
+				}
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if r := <-results; r.resp != nil {
+							r.resp.Body.Close()
+						}
+					}
+				}(pending)
+				res.resp.Body = &cancelOnClose{ReadCloser: res.resp.Body, cancel: cancels[res.i]}
+				return res.resp, nil
+			}
+
+			lastErr = res.err
+			cancels[res.i]()
+			if launched < n {
+				// Fail over immediately rather than waiting for the delay.
+				launch()
+				pending++
+				timer.Reset(t.p.HedgeDelay)
+			} else if pending == 0 {
+				return nil, lastErr
+			}
+		case <-timer.C:
+			if launched < n {
+				launch()
+				pending++
+				timer.Reset(t.p.HedgeDelay)
+			}
+		}
+	}
+}
+
+// cancelOnClose cancels the context of the winning attempt once its body has
+// been consumed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,94 @@
+package skeleton
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestUpstream(t *testing.T, h http.HandlerFunc) *url.URL {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestReverseProxyHedge(t *testing.T) {
+	cancelled := make(chan struct{})
+	slow := newTestUpstream(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(cancelled)
+	})
+	fast := newTestUpstream(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("fast"))
+	})
+
+	p, err := NewReverseProxy(10*time.Millisecond, slow, fast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/items", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "fast" {
+		t.Errorf("got %d %q, want the response of the second upstream", w.Code, w.Body.String())
+	}
+
+	// The losing attempt is cancelled.
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Error("the attempt to the slow upstream was not cancelled")
+	}
+}
+
+func TestReverseProxyHost(t *testing.T) {
+	hosts := make(chan string, 4)
+	upstream := func(w http.ResponseWriter, r *http.Request) {
+		hosts <- r.Host
+	}
+	p, err := NewReverseProxy(time.Second, newTestUpstream(t, upstream), newTestUpstream(t, upstream))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GET is hedged, POST is not; both forward the Host of the client.
+	for _, method := range []string{"GET", "POST"} {
+		r := httptest.NewRequest(method, "/items", nil)
+		r.Host = "api.example.com"
+		p.ServeHTTP(httptest.NewRecorder(), r)
+		if host := <-hosts; host != "api.example.com" {
+			t.Errorf("%s: upstream host = %q, want api.example.com", method, host)
+		}
+	}
+}
+
+func TestReverseProxyNoHedgeWithBody(t *testing.T) {
+	var hedged int32
+	slow := newTestUpstream(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	})
+	other := newTestUpstream(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hedged, 1)
+	})
+
+	p, err := NewReverseProxy(time.Millisecond, slow, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/search", strings.NewReader(`{"q":"x"}`)))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if n := atomic.LoadInt32(&hedged); n != 0 {
+		t.Errorf("request with a body was hedged %d times, want 0", n)
+	}
+}
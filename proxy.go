@@ -0,0 +1,158 @@
+package skeleton
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TrustedProxies is the set of networks from which forwarded headers (e.g.,
+// X-Forwarded-For, X-Forwarded-Proto, Forwarded) are trusted. Headers from
+// any other peer are ignored, as they can be set by the client.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses the provided CIDRs (e.g., 10.0.0.0/8). Plain IP
+// addresses are accepted and treated as a single host.
+func ParseTrustedProxies(cidrs ...string) (TrustedProxies, error) {
+	return ParseCIDRs(cidrs...)
+}
+
+// ParseCIDRs parses the provided CIDRs (e.g., 10.0.0.0/8). Plain IP addresses
+// are accepted and treated as a single host.
+func ParseCIDRs(cidrs ...string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		if !strings.Contains(c, "/") {
+			ip := net.ParseIP(c)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", c)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q. %s", c, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// Trusts returns true if the provided address (with or without a port)
+// belongs to a trusted proxy.
+func (t TrustedProxies) Trusts(addr string) bool {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return false
+	}
+	for _, n := range t {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the IP address of the client which made the request. If
+// the request came from a trusted proxy, X-Forwarded-For is walked from the
+// right, skipping trusted proxies, so that addresses injected by the client
+// are not used.
+func ClientIP(r *http.Request, trusted TrustedProxies) string {
+	ip := remoteHost(r)
+	if !trusted.Trusts(r.RemoteAddr) {
+		return ip
+	}
+
+	var hops []string
+	for _, h := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(h, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !trusted.Trusts(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// ExternalURL returns the base URL of the server as seen by the client (e.g.,
+// https://example.com/app), for building absolute URLs in redirects, Link
+// headers or OAuth callbacks. Behind a proxy, the scheme and host of the
+// request are usually those of the internal hop.
+//
+// If the request came from a trusted proxy, the scheme, host and prefix are
+// read from the Forwarded header (RFC 7239) or, if absent, from
+// X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Prefix. Otherwise, the
+// URL is built from the request itself.
+func ExternalURL(r *http.Request, trusted TrustedProxies) *url.URL {
+	u := &url.URL{Scheme: "http", Host: r.Host}
+	if r.TLS != nil {
+		u.Scheme = "https"
+	}
+	if !trusted.Trusts(r.RemoteAddr) {
+		return u
+	}
+
+	proto, host := forwardedProtoHost(r.Header.Get("Forwarded"))
+	if proto == "" {
+		proto = firstHeaderValue(r.Header.Get("X-Forwarded-Proto"))
+	}
+	if host == "" {
+		host = firstHeaderValue(r.Header.Get("X-Forwarded-Host"))
+	}
+
+	if proto = strings.ToLower(proto); proto == "http" || proto == "https" {
+		u.Scheme = proto
+	}
+	if host != "" && !strings.ContainsAny(host, "/?#@ ") {
+		u.Host = host
+	}
+	if prefix := firstHeaderValue(r.Header.Get("X-Forwarded-Prefix")); prefix != "" {
+		if p := strings.Trim(prefix, "/"); p != "" {
+			u.Path = "/" + p
+		}
+	}
+	return u
+}
+
+// forwardedProtoHost returns the proto and host of the first element of a
+// Forwarded header, which describes the hop closest to the client.
+func forwardedProtoHost(header string) (proto, host string) {
+	first, _, _ := strings.Cut(header, ",")
+	for _, pair := range strings.Split(first, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		v = strings.Trim(v, `"`)
+		switch strings.ToLower(k) {
+		case "proto":
+			proto = v
+		case "host":
+			host = v
+		}
+	}
+	return proto, host
+}
+
+// firstHeaderValue returns the first entry of a comma-separated header value.
+func firstHeaderValue(v string) string {
+	first, _, _ := strings.Cut(v, ",")
+	return strings.TrimSpace(first)
+}
@@ -0,0 +1,77 @@
+package skeleton
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// localRateLimitSweepInterval is the minimum time between sweeps of the
+// buckets of a LocalRateLimitStore.
+const localRateLimitSweepInterval = time.Minute
+
+// LocalRateLimitStore is an in-process RateLimitStore. Limits are not shared
+// between replicas; use RedisRateLimitStore for that purpose.
+//
+// Buckets of idle clients are removed periodically once they have refilled,
+// as they are then equivalent to new buckets, so that memory does not grow
+// with the number of clients ever seen.
+type LocalRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLocalRateLimitStore creates an empty in-process RateLimitStore.
+func NewLocalRateLimitStore() *LocalRateLimitStore {
+	return &LocalRateLimitStore{
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// Take attempts to take a token from the bucket identified by key.
+func (s *LocalRateLimitStore) Take(_ context.Context, key string, rate float64, burst int) (RateLimitResult, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= localRateLimitSweepInterval {
+		s.sweep(now, rate, burst)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return tokenBucketResult(allowed, b.tokens, rate, burst), nil
+}
+
+// sweep removes the buckets which have fully refilled. It must be called
+// with the lock held.
+func (s *LocalRateLimitStore) sweep(now time.Time, rate float64, burst int) {
+	s.lastSweep = now
+	if rate <= 0 {
+		return
+	}
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*rate >= float64(burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
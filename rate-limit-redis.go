@@ -0,0 +1,120 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// RedisEvalFunc evaluates a Lua script on Redis. This package does not depend
+// on a Redis client, so the application provides the evaluation. For
+// example, using github.com/redis/go-redis:
+//
+// ```
+//
+//	func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//	    return client.Eval(ctx, script, keys, args...).Result()
+//	}
+//
+// ```
+type RedisEvalFunc func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+
+// redisTokenBucketScript atomically refills and takes a token from a bucket
+// stored as a hash. The time is taken from the Redis server, so that the
+// clocks of the replicas do not need to agree. The remaining tokens are
+// returned as a string, as Lua numbers are truncated to integers in replies.
+const redisTokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`
+
+// RedisRateLimitStore is a RateLimitStore which keeps its state in Redis, so
+// that limits are shared between all replicas of the server.
+//
+// If Redis is unavailable, the store degrades based on its configuration:
+//   - if Fallback is set, it is used instead (e.g., a LocalRateLimitStore),
+//     which limits each replica independently;
+//   - otherwise, if FailOpen is true, requests are allowed;
+//   - otherwise, the error is returned and the request is rejected.
+type RedisRateLimitStore struct {
+	Eval RedisEvalFunc
+
+	// Prefix is prepended to the keys of the buckets. Defaults to
+	// "ratelimit:".
+	Prefix string
+
+	Fallback RateLimitStore
+	FailOpen bool
+}
+
+// Take attempts to take a token from the bucket identified by key.
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error) {
+	res, err := s.take(ctx, key, rate, burst)
+	if err == nil {
+		return res, nil
+	}
+
+	switch {
+	case s.Fallback != nil:
+		return s.Fallback.Take(ctx, key, rate, burst)
+	case s.FailOpen:
+		return RateLimitResult{Allowed: true, Limit: burst, Remaining: burst}, nil
+	}
+	return RateLimitResult{}, err
+}
+
+func (s *RedisRateLimitStore) take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error) {
+	if rate <= 0 {
+		return RateLimitResult{}, errors.New("rate limit requires a positive rate")
+	}
+
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = "ratelimit:"
+	}
+
+	reply, err := s.Eval(ctx, redisTokenBucketScript, []string{prefix + key}, rate, burst)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	tokensStr, ok := values[1].(string)
+	if !ok {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	return tokenBucketResult(allowed == 1, tokens, rate, burst), nil
+}
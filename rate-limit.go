@@ -0,0 +1,206 @@
+package skeleton
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitResult describes the outcome of taking a token from a rate limit.
+type RateLimitResult struct {
+	// Allowed is true if the request may proceed.
+	Allowed bool
+
+	// Limit is the maximum number of requests which can be made in a burst.
+	Limit int
+
+	// Remaining is the number of requests which can still be made
+	// immediately.
+	Remaining int
+
+	// RetryAfter is the time until a request would be allowed. It is only
+	// set when Allowed is false.
+	RetryAfter time.Duration
+
+	// Reset is the time until the limit is fully replenished.
+	Reset time.Duration
+}
+
+// RateLimitStore holds the state of rate limits. It allows the state to be
+// kept in-process (NewLocalRateLimitStore) or shared between replicas
+// (RedisRateLimitStore).
+type RateLimitStore interface {
+	// Take attempts to take a token from the bucket identified by key, which
+	// is refilled at rate tokens per second up to a maximum of burst tokens.
+	Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error)
+}
+
+// RateLimitHeaderFormat determines the headers describing the state of the
+// rate limit which are sent with responses.
+type RateLimitHeaderFormat int
+
+const (
+	// RateLimitHeadersX sends X-RateLimit-Limit, X-RateLimit-Remaining and
+	// X-RateLimit-Reset, where the reset is a Unix timestamp in seconds.
+	RateLimitHeadersX RateLimitHeaderFormat = iota
+
+	// RateLimitHeadersDraft sends RateLimit-Limit, RateLimit-Remaining and
+	// RateLimit-Reset as defined by the IETF draft
+	// (draft-ietf-httpapi-ratelimit-headers), where the reset is the number
+	// of seconds until the limit is replenished.
+	RateLimitHeadersDraft
+
+	// RateLimitHeadersNone does not send any rate limit headers. Retry-After
+	// is still sent with rejected requests.
+	RateLimitHeadersNone
+)
+
+// RateLimiter limits the rate of requests per client using a token bucket.
+type RateLimiter struct {
+	Store RateLimitStore
+
+	// Rate is the number of requests per second which are replenished.
+	Rate float64
+
+	// Burst is the maximum number of requests which can be made at once.
+	Burst int
+
+	// KeyFunc returns the key identifying the client of a request (e.g., the
+	// ID of the user of the session). Defaults to the client IP, as
+	// determined by ClientIP with TrustedProxies.
+	KeyFunc func(r *http.Request) string
+
+	// TrustedProxies are the proxies whose X-Forwarded-For and Forwarded
+	// headers are honored by the default KeyFunc. Headers from other
+	// clients are ignored, as they could otherwise evade the limit by
+	// forging them.
+	TrustedProxies TrustedProxies
+
+	// HeaderFormat determines the rate limit headers sent with both allowed
+	// and rejected responses. Defaults to RateLimitHeadersX.
+	HeaderFormat RateLimitHeaderFormat
+}
+
+// NewRateLimiter creates a RateLimiter allowing rate requests per second per
+// client, with bursts of up to burst requests. The state is kept in a
+// LocalRateLimitStore; replace Store to share it between replicas.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		Store: NewLocalRateLimitStore(),
+		Rate:  rate,
+		Burst: burst,
+	}
+}
+
+// Allow takes a token for the client of the request.
+func (l *RateLimiter) Allow(r *http.Request) (RateLimitResult, error) {
+	key := ClientIP(r, l.TrustedProxies)
+	if l.KeyFunc != nil {
+		key = l.KeyFunc(r)
+	}
+	return l.Store.Take(r.Context(), key, l.Rate, l.Burst)
+}
+
+// Handler is a middleware which rejects requests exceeding the rate limit
+// with a 429 and a Retry-After header. The state of the limit is sent in the
+// headers selected by HeaderFormat. If the store fails, the request is
+// rejected with a 503.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, err := l.Allow(r)
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		l.setHeaders(w.Header(), res)
+		if !res.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RateLimitMiddleware returns a Middleware equivalent to
+// RateLimiter.Handler, for use with HttpServer.Use.
+//
+// ```
+//
+//	server.Use(skeleton.RateLimitMiddleware(skeleton.NewRateLimiter(10, 20), skeleton.GoRouteContextAdapter))
+//
+// ```
+func RateLimitMiddleware[Ctx any](l *RateLimiter, adapter ContextAdapter[Ctx]) Middleware[Ctx] {
+	return func(next func(Ctx)) func(Ctx) {
+		return func(ctx Ctx) {
+			w, r := adapter(ctx)
+			l.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				next(ctx)
+			})).ServeHTTP(w, r)
+		}
+	}
+}
+
+// setHeaders sets the rate limit headers in the format selected by
+// HeaderFormat.
+func (l *RateLimiter) setHeaders(h http.Header, res RateLimitResult) {
+	limit := strconv.Itoa(res.Limit)
+	remaining := strconv.Itoa(res.Remaining)
+
+	switch l.HeaderFormat {
+	case RateLimitHeadersX:
+		h.Set("X-RateLimit-Limit", limit)
+		h.Set("X-RateLimit-Remaining", remaining)
+		h.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(res.Reset).Unix(), 10))
+	case RateLimitHeadersDraft:
+		h.Set("RateLimit-Limit", limit)
+		h.Set("RateLimit-Remaining", remaining)
+		h.Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil(res.Reset.Seconds()))))
+	}
+}
+
+// remoteHost returns the host portion of the remote address of the request.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// tokenBucketResult computes the RateLimitResult for a bucket holding the
+// provided number of tokens after the request has been accounted for.
+func tokenBucketResult(allowed bool, tokens, rate float64, burst int) RateLimitResult {
+	res := RateLimitResult{
+		Allowed:   allowed,
+		Limit:     burst,
+		Remaining: int(math.Floor(tokens)),
+	}
+	if rate <= 0 {
+		return res
+	}
+	res.Reset = secondsToDuration((float64(burst) - tokens) / rate)
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+// ceilSeconds rounds d up to a whole number of seconds, with a minimum of 1.
+func ceilSeconds(d time.Duration) int {
+	s := int(math.Ceil(d.Seconds()))
+	if s < 1 {
+		return 1
+	}
+	return s
+}
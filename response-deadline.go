@@ -0,0 +1,25 @@
+package skeleton
+
+import (
+	"net/http"
+	"time"
+)
+
+// SetWriteDeadline sets the deadline for writing the response to w, allowing
+// streaming handlers to bound slow readers without a global
+// http.Server.WriteTimeout, which would break long streams. The deadline can
+// be extended repeatedly (e.g., before writing each chunk). A zero time
+// removes the deadline. Writes after the deadline fail.
+//
+// The ResponseWriters of this package can be passed directly, as they
+// expose the underlying writer. http.ErrNotSupported is returned if the
+// underlying writer does not support deadlines.
+func SetWriteDeadline(w http.ResponseWriter, t time.Time) error {
+	return http.NewResponseController(w).SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for reading the request body from w. See
+// SetWriteDeadline.
+func SetReadDeadline(w http.ResponseWriter, t time.Time) error {
+	return http.NewResponseController(w).SetReadDeadline(t)
+}
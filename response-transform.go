@@ -0,0 +1,146 @@
+package skeleton
+
+import (
+	"bufio"
+	"errors"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// ResponseTransformer rewrites the body of a response after the handler has
+// written it. The status and header of the response are provided; the
+// header may be modified. The returned body replaces the original.
+type ResponseTransformer func(r *http.Request, status int, header http.Header, body []byte) ([]byte, error)
+
+// Transform returns a middleware which post-processes response bodies (e.g.,
+// wrapping JSON responses in an envelope, or injecting links). Only
+// responses whose Content-Type is one of contentTypes (by default,
+// application/json) are transformed; others are streamed through untouched.
+//
+// Matching responses are buffered in full so that they can be rewritten,
+// which costs memory proportional to the size of the response and prevents
+// streaming (Flush has no effect). Avoid applying it to routes with large
+// or streamed responses. If the transformer fails, a 500 is written instead.
+func Transform(fn ResponseTransformer, contentTypes ...string) func(http.Handler) http.Handler {
+	if len(contentTypes) == 0 {
+		contentTypes = []string{"application/json"}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tw := &transformResponseWriter{
+				ResponseWriter: w,
+				contentTypes:   contentTypes,
+			}
+			next.ServeHTTP(tw, r)
+			tw.finish(r, fn)
+		})
+	}
+}
+
+// transformResponseWriter decides whether to buffer the response when the
+// header is written, based on its Content-Type.
+type transformResponseWriter struct {
+	http.ResponseWriter
+	contentTypes []string
+
+	decided bool
+	rec     *responseRecorder
+}
+
+func (w *transformResponseWriter) decide(status int) {
+	w.decided = true
+	if !bodyAllowedForStatus(status) || !w.matches(w.Header().Get("Content-Type")) {
+		return
+	}
+	// Share the header, so that changes made by the handler after this
+	// point are kept.
+	w.rec = &responseRecorder{header: w.Header()}
+}
+
+func (w *transformResponseWriter) matches(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, ct := range w.contentTypes {
+		if ct == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *transformResponseWriter) WriteHeader(status int) {
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if !w.decided {
+		w.decide(status)
+	}
+	if w.rec != nil {
+		w.rec.WriteHeader(status)
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *transformResponseWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.rec != nil {
+		return w.rec.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush forwards to the underlying writer, unless the response is being
+// buffered.
+func (w *transformResponseWriter) Flush() {
+	if w.rec != nil {
+		return
+	}
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack allows the connection to be taken over (e.g., for WebSockets),
+// unless the response is already being buffered.
+func (w *transformResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.rec != nil {
+		return nil, nil, errors.New("cannot hijack a response which is being transformed")
+	}
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *transformResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish transforms and writes the buffered response, if any.
+func (w *transformResponseWriter) finish(r *http.Request, fn ResponseTransformer) {
+	if w.rec == nil {
+		return
+	}
+	res := w.rec.result()
+
+	h := w.ResponseWriter.Header()
+	body, err := fn(r, res.status, h, res.body)
+	if err != nil {
+		h.Del("Content-Type")
+		h.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteHeader(res.status)
+	_, _ = w.ResponseWriter.Write(body)
+}
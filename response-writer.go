@@ -0,0 +1,152 @@
+package skeleton
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// statusCapturingResponseWriter wraps an http.ResponseWriter to record the
+// status code and the number of bytes written to the response. It also
+// allows a hook to be run just before the header is written.
+//
+// Only the first call to WriteHeader is forwarded. Subsequent calls are
+// ignored, and the status they attempted to write is recorded, so that the
+// status of the response is deterministic and net/http does not log a
+// superfluous WriteHeader call.
+//
+// http.Flusher and http.Hijacker are forwarded to the underlying writer, and
+// Unwrap allows http.ResponseController to reach it.
+type statusCapturingResponseWriter struct {
+	http.ResponseWriter
+
+	status      int
+	bytes       int64
+	wroteHeader bool
+	hijacked    bool
+
+	// duplicateStatus is the status of the last ignored call to WriteHeader.
+	duplicateStatus int
+
+	// empty is set by the server when the handler returned without writing
+	// a response.
+	empty bool
+
+	// pattern and service identify the matched route, for metrics.
+	pattern string
+	service string
+
+	// capture, if set, receives a copy of the response body, which is
+	// validated against the documented schema.
+	capture    *responseCapture
+	violations ValidationErrors
+
+	// beforeWrite is called once, just before the header is written.
+	beforeWrite func()
+}
+
+// newStatusCapturingResponseWriter wraps w, unless it has already been
+// wrapped, in which case the existing wrapper is returned.
+func newStatusCapturingResponseWriter(w http.ResponseWriter) *statusCapturingResponseWriter {
+	if cw, ok := w.(*statusCapturingResponseWriter); ok {
+		return cw
+	}
+	return &statusCapturingResponseWriter{ResponseWriter: w}
+}
+
+func (w *statusCapturingResponseWriter) WriteHeader(status int) {
+	// Informational responses can be sent any number of times before the
+	// final header.
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.wroteHeader {
+		w.duplicateStatus = status
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	if w.beforeWrite != nil {
+		w.beforeWrite()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusCapturingResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	if w.capture != nil {
+		w.capture.write(b[:n])
+	}
+	return n, err
+}
+
+// Flush sends any buffered data to the client.
+func (w *statusCapturingResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack allows the connection to be taken over, e.g., for WebSockets.
+// Libraries which assert http.Hijacker (such as gorilla/websocket) can
+// therefore upgrade the connection from a route handler. Once hijacked, the
+// server no longer writes to the response.
+func (w *statusCapturingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("underlying ResponseWriter does not implement http.Hijacker")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *statusCapturingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the status code which was written, or 0 if nothing has
+// been written yet.
+func (w *statusCapturingResponseWriter) Status() int {
+	return w.status
+}
+
+// BytesWritten returns the number of bytes of the body which were written.
+func (w *statusCapturingResponseWriter) BytesWritten() int64 {
+	return w.bytes
+}
+
+// Written returns true if the header has been written.
+func (w *statusCapturingResponseWriter) Written() bool {
+	return w.wroteHeader
+}
+
+// DuplicateStatus returns the status of the last call to WriteHeader which
+// was ignored because the header had already been written, or 0 if there was
+// none.
+func (w *statusCapturingResponseWriter) DuplicateStatus() int {
+	return w.duplicateStatus
+}
+
+// ResponseViolations returns the ways in which the response violated the
+// schema documented for the route. See HttpServer.ValidateResponses.
+func (w *statusCapturingResponseWriter) ResponseViolations() ValidationErrors {
+	return w.violations
+}
+
+// Hijacked returns true if the connection has been hijacked.
+func (w *statusCapturingResponseWriter) Hijacked() bool {
+	return w.hijacked
+}
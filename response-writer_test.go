@@ -0,0 +1,48 @@
+package skeleton
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+func TestStatusCapturingResponseWriterDoubleWriteHeader(t *testing.T) {
+	rec := httptest.NewRecorder()
+	cw := newStatusCapturingResponseWriter(rec)
+	cw.WriteHeader(http.StatusCreated)
+	cw.WriteHeader(http.StatusInternalServerError)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("written status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if got := cw.Status(); got != http.StatusCreated {
+		t.Errorf("Status() = %d, want %d", got, http.StatusCreated)
+	}
+	if got := cw.DuplicateStatus(); got != http.StatusInternalServerError {
+		t.Errorf("DuplicateStatus() = %d, want %d", got, http.StatusInternalServerError)
+	}
+}
+
+func TestHttpServerDoubleWriteHeader(t *testing.T) {
+	router := GoRouter[*gorouter.RouteContext]()
+	router.AddRoute(GoRoute("POST", "/users", func(ctx *gorouter.RouteContext) {
+		ctx.W.WriteHeader(http.StatusCreated)
+		ctx.W.WriteHeader(http.StatusInternalServerError)
+		ctx.W.Write([]byte("created"))
+	}))
+	server := NewHttpServer[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]](
+		"", nil, router, &GoHttpServerDelegate{})
+
+	rec, err := server.TestRequest("POST", "/users", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusCreated {
+		t.Errorf("status = %d, want the first status %d", rec.Code, http.StatusCreated)
+	}
+	if rec.Body.String() != "created" {
+		t.Errorf("body = %q, want %q", rec.Body.String(), "created")
+	}
+}
@@ -0,0 +1,28 @@
+package skeleton
+
+// ErrorReturningRoute is an optional interface for routes whose handler
+// returns an error, rather than writing the error response itself. A
+// returned error is returned by Serve, and so rendered through the error
+// handler of the server (see HttpServer.ErrorHandler) and logged by the
+// LoggingHttpServer. Use ErrorStatus (e.g., through an *HttpError) to control
+// the status.
+//
+// Errors returned after the handler has written the response are only
+// logged, as the response can no longer be changed.
+type ErrorReturningRoute[Ctx any] interface {
+	GetErrorHandler() func(Ctx) error
+}
+
+// errorRouteHandler returns the handler of the route, which stores the error
+// returned by the route in err, if the route implements ErrorReturningRoute.
+// Otherwise, handler is returned.
+func errorRouteHandler[Ctx any](route any, handler func(Ctx), err *error) func(Ctx) {
+	er, ok := routeAs[ErrorReturningRoute[Ctx]](route)
+	if !ok {
+		return handler
+	}
+	fn := er.GetErrorHandler()
+	return func(ctx Ctx) {
+		*err = fn(ctx)
+	}
+}
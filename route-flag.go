@@ -0,0 +1,45 @@
+package skeleton
+
+import (
+	"errors"
+)
+
+var (
+	ErrRouteDisabled = errors.New("route is disabled")
+)
+
+// FlaggedRoute is an optional interface for routes which can be enabled and
+// disabled at runtime (e.g., based on a feature flag). When RouteEnabled
+// returns false, the route is treated as if it did not exist, so the request
+// results in a *RouteNotFoundError (and a 404) rather than reaching a
+// "disabled" stub.
+//
+// RouteEnabled is called for every request matching the route, concurrently,
+// so it must be safe for concurrent use. Toggling the flag takes effect for
+// the next matching request; requests already being handled are unaffected.
+// A convenient implementation is the Load method of an atomic.Bool.
+type FlaggedRoute interface {
+	RouteEnabled() bool
+}
+
+// routeEnabled returns false if the route, or any route it wraps, is a
+// disabled FlaggedRoute.
+func routeEnabled(route any) bool {
+	fr, ok := routeAs[FlaggedRoute](route)
+	return !ok || fr.RouteEnabled()
+}
+
+// AddRouteIf adds the routes to the router only if enabled returns true. The
+// predicate is evaluated once, at registration. To toggle routes at runtime,
+// register them as FlaggedRoute instead (e.g., using GoRouteWithFlag).
+func AddRouteIf[Ctx any, R Route[Ctx]](router Router[Ctx, R], enabled func() bool, routes ...Route[Ctx]) error {
+	if !enabled() {
+		return nil
+	}
+	for _, route := range routes {
+		if err := router.AddRoute(route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package skeleton
+
+import (
+	"strings"
+)
+
+// RouteGroup is a Router which adds routes to an underlying Router with a
+// shared path prefix and middleware. The middleware of a group only runs for
+// the routes added through it (or its nested groups), inside any middleware
+// registered with HttpServer.Use and outside the middleware of the route
+// itself. Groups can be nested; the prefixes are concatenated, and the
+// middleware of outer groups runs first.
+//
+// Routes added to a group need to report their method and path (e.g., routes
+// created by GoRoute), otherwise ErrInvalidRoute is returned.
+//
+// ```
+//
+//	api := server.Group("/api", authMiddleware)
+//	api.AddRoute(skeleton.GoRoute("GET", "/users", listUsers)) // GET /api/users
+//
+//	admin := api.Group("/admin", adminMiddleware)
+//	admin.AddRoute(skeleton.GoRoute("GET", "/stats", stats)) // GET /api/admin/stats
+//
+// ```
+type RouteGroup[Ctx any, R Route[Ctx]] struct {
+	Router[Ctx, R]
+
+	prefix     string
+	middleware []Middleware[Ctx]
+}
+
+// NewRouteGroup creates a RouteGroup adding routes to the router.
+func NewRouteGroup[Ctx any, R Route[Ctx]](router Router[Ctx, R], prefix string, mw ...Middleware[Ctx]) *RouteGroup[Ctx, R] {
+	return &RouteGroup[Ctx, R]{
+		Router:     router,
+		prefix:     strings.TrimSuffix(prefix, "/"),
+		middleware: mw,
+	}
+}
+
+// Group creates a RouteGroup adding routes to the router of the server.
+func (s *HttpServer[Ctx, R]) Group(prefix string, mw ...Middleware[Ctx]) *RouteGroup[Ctx, R] {
+	return NewRouteGroup[Ctx, R](s.R, prefix, mw...)
+}
+
+// Group creates a nested group, whose prefix and middleware are added to
+// those of g.
+func (g *RouteGroup[Ctx, R]) Group(prefix string, mw ...Middleware[Ctx]) *RouteGroup[Ctx, R] {
+	return NewRouteGroup[Ctx, R](g, prefix, mw...)
+}
+
+// AddRoute adds the route to the underlying router, with the prefix of the
+// group prepended to its path.
+func (g *RouteGroup[Ctx, R]) AddRoute(route Route[Ctx]) error {
+	pr, ok := route.(groupableRoute[Ctx])
+	if !ok {
+		return ErrInvalidRoute
+	}
+	return g.Router.AddRoute(&groupRoute[Ctx]{
+		route:      pr,
+		path:       g.prefix + pr.GetPath(),
+		middleware: g.middleware,
+	})
+}
+
+// groupableRoute is a route which reports its method and path.
+type groupableRoute[Ctx any] interface {
+	Route[Ctx]
+	GetMethod() string
+	GetPath() string
+}
+
+// groupRoute is a route added through a RouteGroup. It reports the prefixed
+// path and carries the middleware of the group, followed by the middleware
+// of the original route (see MiddlewareRoute). As it implements
+// WrappedRoute, the other optional interfaces of the original route (e.g.,
+// MetadataRoute) are still found.
+type groupRoute[Ctx any] struct {
+	route      groupableRoute[Ctx]
+	path       string
+	middleware []Middleware[Ctx]
+
+	// paramNames are the names of the placeholders of the prefixed path,
+	// which the router may record while adding the route.
+	paramNames []string
+}
+
+func (r *groupRoute[Ctx]) GetMethod() string {
+	return r.route.GetMethod()
+}
+
+func (r *groupRoute[Ctx]) GetPath() string {
+	return r.path
+}
+
+func (r *groupRoute[Ctx]) GetHandler() func(Ctx) {
+	return r.route.GetHandler()
+}
+
+// GetParamNames returns the names of the placeholders of the prefixed path.
+// Along with AddParamName, it allows the route to be added to GoRouter.
+func (r *groupRoute[Ctx]) GetParamNames() []string {
+	return r.paramNames
+}
+
+// AddParamName records the name of a placeholder of the prefixed path. The
+// names are kept by the group route rather than the original one, as the
+// prefix may itself contain placeholders.
+func (r *groupRoute[Ctx]) AddParamName(name string) {
+	r.paramNames = append(r.paramNames, name)
+}
+
+// Middlewares returns the middleware of the group, followed by that of the
+// original route. It implements MiddlewareRoute.
+func (r *groupRoute[Ctx]) Middlewares() []Middleware[Ctx] {
+	mw := append([]Middleware[Ctx](nil), r.middleware...)
+	if mr, ok := routeAs[MiddlewareRoute[Ctx]](r.route); ok {
+		mw = append(mw, mr.Middlewares()...)
+	}
+	return mw
+}
+
+// UnwrapRoute returns the original route. It implements WrappedRoute.
+func (r *groupRoute[Ctx]) UnwrapRoute() any {
+	return r.route
+}
@@ -0,0 +1,34 @@
+package skeleton
+
+import (
+	"testing"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+func TestRouteGroupGoRouter(t *testing.T) {
+	router := GoRouter[*gorouter.RouteContext]()
+	api := NewRouteGroup[*gorouter.RouteContext](router, "/orgs/:org")
+	users := api.Group("/users")
+
+	noop := func(*gorouter.RouteContext) {}
+	if err := users.AddRoute(GoRoute("GET", "/:id", noop)); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+
+	route, err := router.Match("GET", "/orgs/acme/users/42")
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	if got := route.Pattern(); got != "/orgs/:org/users/:id" {
+		t.Errorf("Pattern() = %q, want %q", got, "/orgs/:org/users/:id")
+	}
+	params := route.PathParams()
+	if params["org"] != "acme" || params["id"] != "42" {
+		t.Errorf("PathParams() = %v, want org=acme and id=42", params)
+	}
+
+	if _, err := router.Match("GET", "/users/42"); err == nil {
+		t.Error("Match without the group prefix should fail")
+	}
+}
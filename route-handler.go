@@ -0,0 +1,36 @@
+package skeleton
+
+import (
+	"context"
+	"net/http"
+)
+
+// ContextAdapter retrieves the ResponseWriter and Request from a
+// RouteContext. As route handlers are of the form func(Ctx), where Ctx can
+// be anything, helpers in this package which need to write a response
+// require an adapter for the Ctx of the application. For
+// gorouter.RouteContext, use GoRouteContextAdapter.
+type ContextAdapter[Ctx any] func(ctx Ctx) (http.ResponseWriter, *http.Request)
+
+// HandlerFunc converts an http.Handler into a route handler, using the
+// adapter to retrieve the ResponseWriter and Request from the context.
+func HandlerFunc[Ctx any](h http.Handler, adapter ContextAdapter[Ctx]) func(Ctx) {
+	return func(ctx Ctx) {
+		w, r := adapter(ctx)
+		h.ServeHTTP(w, r)
+	}
+}
+
+// RequestContext returns the context of the request being handled, using the
+// adapter to retrieve the request from the route context. The context is
+// cancelled when the client disconnects, when the request has been served,
+// when the HttpServer.RequestTimeout of the route elapses, or when the
+// server is shut down forcefully, so it should be passed to database queries
+// and outbound calls (e.g., db.QueryContext) to free their resources early.
+func RequestContext[Ctx any](ctx Ctx, adapter ContextAdapter[Ctx]) context.Context {
+	_, r := adapter(ctx)
+	if r == nil {
+		return context.Background()
+	}
+	return r.Context()
+}
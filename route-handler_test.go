@@ -0,0 +1,99 @@
+package skeleton
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+// blockingDriver is a database driver whose queries block until their
+// context is cancelled, like a slow query would.
+type blockingDriver struct {
+	started chan struct{}
+}
+
+func (d *blockingDriver) Open(name string) (driver.Conn, error) {
+	return &blockingConn{started: d.started}, nil
+}
+
+type blockingConn struct {
+	started chan struct{}
+}
+
+func (c *blockingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *blockingConn) Close() error {
+	return nil
+}
+
+func (c *blockingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *blockingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	close(c.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRequestContextCancelsQueryOnDisconnect(t *testing.T) {
+	started := make(chan struct{})
+	db := sql.OpenDB(driverConnector{&blockingDriver{started: started}})
+	defer db.Close()
+
+	queryErr := make(chan error, 1)
+	router := GoRouter[*gorouter.RouteContext]()
+	router.AddRoute(GoRoute("GET", "/report", func(ctx *gorouter.RouteContext) {
+		_, err := db.QueryContext(GoRequestContext(ctx), "SELECT slow()")
+		queryErr <- err
+	}))
+	server := NewHttpServer[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]](
+		"", nil, router, &GoHttpServerDelegate{})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL+"/report", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		<-started
+		// The client disconnects while the query is running.
+		cancel()
+	}()
+	if _, err := http.DefaultClient.Do(req); err == nil {
+		t.Fatal("request succeeded, want it to be cancelled")
+	}
+
+	select {
+	case err := <-queryErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("query error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("query was not cancelled after the client disconnected")
+	}
+}
+
+// driverConnector opens connections of a driver.Driver for sql.OpenDB.
+type driverConnector struct {
+	d driver.Driver
+}
+
+func (c driverConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.d.Open("")
+}
+
+func (c driverConnector) Driver() driver.Driver {
+	return c.d
+}
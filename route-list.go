@@ -0,0 +1,51 @@
+package skeleton
+
+import "net/http"
+
+// Routes returns the routes which were added to the router of the server, in
+// the order they were added, if the router implements RouteLister.
+// Otherwise, nil is returned. It allows the server itself to be used as a
+// RouteLister (e.g., for OpenAPIHandler).
+func (s *HttpServer[Ctx, R]) Routes() []RouteInfo {
+	if l, ok := s.R.(RouteLister); ok {
+		return l.Routes()
+	}
+	return nil
+}
+
+// routeListEntry is an entry of the response of RoutesHandler.
+type routeListEntry struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// RoutesHandler returns an http.Handler which responds with the method and
+// path of the routes of the lister as JSON, for debugging. The handler is
+// protected by guard, as the routes reveal the surface of the API; if guard
+// is nil, every request is rejected.
+//
+// Only the routes added through the Router are listed. Routes added directly
+// to the underlying gorouter.RouterNode bypass it, and are not listed.
+func RoutesHandler(lister RouteLister, guard *AdminGuard) http.Handler {
+	return guard.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		routes := lister.Routes()
+		entries := make([]routeListEntry, 0, len(routes))
+		for _, ri := range routes {
+			entries = append(entries, routeListEntry{Method: ri.Method, Path: ri.Path})
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		_ = WriteJSON(w, http.StatusOK, entries)
+	}))
+}
+
+// RoutesRoute creates a GET route at the provided path which lists the routes
+// of the lister. See RoutesHandler.
+//
+// ```
+//
+//	router.AddRoute(skeleton.RoutesRoute("/admin/routes", server, guard, skeleton.GoRouteContextAdapter))
+//
+// ```
+func RoutesRoute[Ctx any](path string, lister RouteLister, guard *AdminGuard, adapter ContextAdapter[Ctx]) Route[Ctx] {
+	return GoHandlerRoute(http.MethodGet, path, RoutesHandler(lister, guard), adapter)
+}
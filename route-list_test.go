@@ -0,0 +1,20 @@
+package skeleton
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+func TestRoutesHandlerNilGuard(t *testing.T) {
+	router := GoRouter[*gorouter.RouteContext]()
+	router.AddRoute(GoRoute("GET", "/users", func(ctx *gorouter.RouteContext) {}))
+
+	w := httptest.NewRecorder()
+	RoutesHandler(router.(RouteLister), nil).ServeHTTP(w, httptest.NewRequest("GET", "/admin/routes", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
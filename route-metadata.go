@@ -0,0 +1,124 @@
+package skeleton
+
+import (
+	"time"
+)
+
+// RouteMetadata describes optional, per-route behaviour of the server. Routes
+// provide their metadata by implementing MetadataRoute.
+type RouteMetadata struct {
+	// MaxConcurrency limits the number of requests to the route which can be
+	// processed at the same time. Requests beyond the limit receive a 503
+	// with a Retry-After header. A value of 0 means unlimited.
+	MaxConcurrency int
+
+	// ConcurrencyTimeout is the time a request waits for a slot when the
+	// route is at MaxConcurrency, before being rejected. A value of 0 rejects
+	// immediately.
+	ConcurrencyTimeout time.Duration
+
+	// RequireAuth marks the route as protected. Unauthenticated requests to
+	// the route are rejected with a 401 before the handler is called. See
+	// HttpServer.Authenticate.
+	RequireAuth bool
+
+	// Coalesce shares a single execution of the handler between identical
+	// concurrent GET and HEAD requests, protecting the backend from
+	// stampedes on hot keys. Requests are identical if they have the same
+	// HttpServer.CoalesceKey (by default, the method, path and query).
+	//
+	// The response is buffered and copied to every request, so only enable
+	// it for routes whose responses do not depend on the user. Set-Cookie is
+	// only sent to the request which ran the handler.
+	Coalesce bool
+
+	// Service is the logical service or module owning the route (e.g.,
+	// billing), in a server hosting several of them. It is added as a field
+	// to the logs of the LoggingHttpServer, and as a label to metrics. It
+	// needs to be one of HttpServer.Services.
+	Service string
+
+	// Priority is the importance of the route when the server is under load
+	// (see LoadShedder); higher is more important. A value of 0 means
+	// DefaultPriority.
+	Priority int
+
+	// Timeout overrides HttpServer.RequestTimeout for the route. A negative
+	// value disables the timeout for the route (e.g., for streaming).
+	Timeout time.Duration
+
+	// Doc documents the route in the OpenAPI document generated by
+	// OpenAPIHandler.
+	Doc *RouteDoc
+}
+
+// MetadataRoute is an optional interface for routes which carry
+// RouteMetadata.
+type MetadataRoute interface {
+	Metadata() RouteMetadata
+}
+
+// WrappedRoute is an optional interface for routes which wrap another route.
+// For example, a Router may return a route which wraps the route that was
+// originally added, such as GoRouterRoute. Optional route interfaces (e.g.,
+// MetadataRoute) are looked up through the wrapped routes.
+type WrappedRoute interface {
+	UnwrapRoute() any
+}
+
+// routeAs finds the first route in the chain of wrapped routes which
+// implements T.
+func routeAs[T any](route any) (T, bool) {
+	for route != nil {
+		if v, ok := route.(T); ok {
+			return v, true
+		}
+		w, ok := route.(WrappedRoute)
+		if !ok {
+			break
+		}
+		route = w.UnwrapRoute()
+	}
+	var zero T
+	return zero, false
+}
+
+// routeMetadata returns the metadata of the provided route, as well as the
+// MetadataRoute which provided it. If the route does not carry metadata, the
+// zero value is returned.
+func routeMetadata(route any) (RouteMetadata, MetadataRoute) {
+	mr, ok := routeAs[MetadataRoute](route)
+	if !ok {
+		return RouteMetadata{}, nil
+	}
+	return mr.Metadata(), mr
+}
+
+// TimeoutRoute is an optional interface for routes which bound the time
+// spent serving them (e.g., 60s for a report, while most routes need 2s). It
+// overrides RouteMetadata.Timeout and HttpServer.RequestTimeout. A negative
+// value disables the timeout for the route.
+//
+// The deadline applies to the context of the request for as long as the
+// handler runs, including while a response is being streamed. Streaming
+// handlers (e.g., server-sent events) should therefore disable the timeout,
+// or stop streaming once the context is done.
+type TimeoutRoute interface {
+	Timeout() time.Duration
+}
+
+// requestTimeout returns the timeout of a request to the route, given the
+// default timeout of the server.
+func requestTimeout(def time.Duration, route any, meta RouteMetadata) time.Duration {
+	timeout := meta.Timeout
+	if tr, ok := routeAs[TimeoutRoute](route); ok {
+		timeout = tr.Timeout()
+	}
+	switch {
+	case timeout < 0:
+		return 0
+	case timeout > 0:
+		return timeout
+	}
+	return def
+}
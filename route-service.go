@@ -0,0 +1,22 @@
+package skeleton
+
+// UnregisteredService is the service tag used for routes whose
+// RouteMetadata.Service is not one of the registered services.
+const UnregisteredService = "other"
+
+// serviceTag returns the service tag of the route, as used in logs and
+// metrics. To keep the cardinality of the tag bounded, only services in the
+// registered set are reported; any other value results in
+// UnregisteredService. Routes without a service result in an empty string.
+func serviceTag(route any, registered []string) string {
+	meta, _ := routeMetadata(route)
+	if meta.Service == "" {
+		return ""
+	}
+	for _, s := range registered {
+		if s == meta.Service {
+			return s
+		}
+	}
+	return UnregisteredService
+}
@@ -0,0 +1,54 @@
+package skeleton
+
+import (
+	"context"
+	"net/http"
+)
+
+// DefaultTier is the tier of requests for which HttpServer.ResolveTier is not
+// provided or does not resolve a tier.
+const DefaultTier = "default"
+
+type tierKey struct{}
+
+// Tier returns the tier of the request stored in the context by the
+// HttpServer, or DefaultTier if there is none.
+func Tier(ctx context.Context) string {
+	if t, ok := ctx.Value(tierKey{}).(string); ok && t != "" {
+		return t
+	}
+	return DefaultTier
+}
+
+// WithTier returns a copy of ctx carrying the provided tier.
+func WithTier(ctx context.Context, tier string) context.Context {
+	return context.WithValue(ctx, tierKey{}, tier)
+}
+
+// TieredRoute is an optional interface for routes with handler variants per
+// tier (e.g., a paid API key receiving a higher-capability handler). Routes
+// should return their default handler for tiers without a variant.
+type TieredRoute[Ctx any] interface {
+	HandlerForTier(tier string) func(Ctx)
+}
+
+// resolveTier stores the tier of the request in its context, if
+// HttpServer.ResolveTier is provided and resolves one.
+func (s *HttpServer[Ctx, R]) resolveTier(r *http.Request, sess Session) *http.Request {
+	if s.ResolveTier == nil {
+		return r
+	}
+	tier := s.ResolveTier(r, sess)
+	if tier == "" {
+		return r
+	}
+	return r.WithContext(WithTier(r.Context(), tier))
+}
+
+// routeHandler returns the handler of the route for the tier of the request.
+func routeHandler[Ctx any](route Route[Ctx], r *http.Request) func(Ctx) {
+	if tr, ok := routeAs[TieredRoute[Ctx]](route); ok {
+		return tr.HandlerForTier(Tier(r.Context()))
+	}
+	return route.GetHandler()
+}
@@ -1,10 +1,12 @@
 package skeleton
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"sort"
+	"sync"
 
-	"cloud.google.com/go/logging"
 	"github.com/cyc-ttn/gorouter"
 	"github.com/monstercat/golib/logger"
 )
@@ -13,6 +15,14 @@ var (
 	ErrInvalidRoute = errors.New("invalid route")
 )
 
+// Ensure that the delegates thread the ResponseWriter, Request, route and
+// session through the same Generate signature as HttpServer expects.
+var (
+	_ HttpServerDelegate[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]]        = &GoHttpServerDelegate{}
+	_ LoggingHttpServerDelegate[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]] = &LoggingGoHttpServerDelegate{}
+	_ HttpServerDelegate[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]]        = &HttpServerDelegateBridge[*gorouter.RouteContext, *GoRouterRoute[*gorouter.RouteContext]]{}
+)
+
 // GoRouterRoute wraps gorouter.Route[R] so that it includes a
 // gorouter.RouteContext object.
 type GoRouterRoute[R any] struct {
@@ -20,10 +30,31 @@ type GoRouterRoute[R any] struct {
 	gorouter.Route[R]
 }
 
+// Pattern returns the pattern of the matched route (e.g., /users/:id). It
+// implements PatternRoute.
+func (r *GoRouterRoute[R]) Pattern() string {
+	return r.Route.GetPath()
+}
+
+// PathParams returns the parameters extracted from the requested path. It
+// implements ParamsRoute.
+func (r *GoRouterRoute[R]) PathParams() map[string]string {
+	return r.RouteContext.Params
+}
+
+// UnwrapRoute returns the route which was originally added to the router. It
+// implements WrappedRoute.
+func (r *GoRouterRoute[R]) UnwrapRoute() any {
+	return r.Route
+}
+
 // wrapGoRouter is a special struct that wraps gorouter, so that it properly
 // implements Router.
 type wrapGoRouter[Ctx any] struct {
 	*gorouter.RouterNode[Ctx]
+
+	mu     sync.RWMutex
+	routes []RouteInfo
 }
 
 // AddRoute adds a route to the router. The AddRoute function here requires
@@ -34,7 +65,26 @@ func (r *wrapGoRouter[Ctx]) AddRoute(route Route[Ctx]) error {
 	if !ok {
 		return ErrInvalidRoute
 	}
-	return r.RouterNode.AddRoute(rV)
+	if err := r.RouterNode.AddRoute(rV); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, RouteInfo{
+		Method: rV.GetMethod(),
+		Path:   rV.GetPath(),
+		Route:  route,
+	})
+	return nil
+}
+
+// Routes returns the routes which were added to the router. It implements
+// RouteLister.
+func (r *wrapGoRouter[Ctx]) Routes() []RouteInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]RouteInfo(nil), r.routes...)
 }
 
 // Match should match the provided method and path to a route. If nil is
@@ -47,6 +97,9 @@ func (r *wrapGoRouter[Ctx]) Match(method, path string) (*GoRouterRoute[Ctx], err
 	ctx := &gorouter.RouteContext{}
 	route, err := r.RouterNode.Match(method, path, ctx)
 	if err != nil {
+		if allowed := r.allowedMethods(method, path); len(allowed) > 0 {
+			return nil, NewMethodNotAllowedError(method, path, allowed)
+		}
 		return nil, err
 	}
 	return &GoRouterRoute[Ctx]{
@@ -55,6 +108,31 @@ func (r *wrapGoRouter[Ctx]) Match(method, path string) (*GoRouterRoute[Ctx], err
 	}, nil
 }
 
+// allowedMethods returns the methods, other than method, of the routes
+// matching the path. Only the methods which were added to the router are
+// tried.
+func (r *wrapGoRouter[Ctx]) allowedMethods(method, path string) []string {
+	r.mu.RLock()
+	seen := map[string]bool{method: true}
+	var methods []string
+	for _, ri := range r.routes {
+		if !seen[ri.Method] {
+			seen[ri.Method] = true
+			methods = append(methods, ri.Method)
+		}
+	}
+	r.mu.RUnlock()
+
+	var allowed []string
+	for _, m := range methods {
+		if _, err := r.RouterNode.Match(m, path, &gorouter.RouteContext{}); err == nil {
+			allowed = append(allowed, m)
+		}
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
 // GoRouter providers a Router which can be used in skeleton.HttpServer or
 // skeleton.LoggingHttpServer.
 func GoRouter[Ctx any]() Router[Ctx, *GoRouterRoute[Ctx]] {
@@ -73,6 +151,182 @@ func GoRoute[Ctx any](method string, path string, fn func(ctx Ctx)) Route[Ctx] {
 	}
 }
 
+// GoHandlerRoute creates a skeleton.Route, similar to GoRoute, which serves
+// the provided http.Handler.
+func GoHandlerRoute[Ctx any](method string, path string, h http.Handler, adapter ContextAdapter[Ctx]) Route[Ctx] {
+	return GoRoute(method, path, HandlerFunc(h, adapter))
+}
+
+// GoRouteContextAdapter is a ContextAdapter for gorouter.RouteContext.
+func GoRouteContextAdapter(ctx *gorouter.RouteContext) (http.ResponseWriter, *http.Request) {
+	return ctx.W, ctx.R
+}
+
+// GoRequestContext returns the cancelable context of the request carried by
+// the gorouter.RouteContext. See RequestContext.
+func GoRequestContext(ctx *gorouter.RouteContext) context.Context {
+	return RequestContext(ctx, GoRouteContextAdapter)
+}
+
+// GoMetadataRoute is a gorouter.DefaultRoute which carries RouteMetadata.
+type GoMetadataRoute[Ctx any] struct {
+	*gorouter.DefaultRoute[Ctx]
+	Meta RouteMetadata
+}
+
+// Metadata returns the metadata of the route. It implements MetadataRoute.
+func (r *GoMetadataRoute[Ctx]) Metadata() RouteMetadata {
+	return r.Meta
+}
+
+// GoErrorRoute is a gorouter.DefaultRoute whose handler returns an error. See
+// ErrorReturningRoute.
+type GoErrorRoute[Ctx any] struct {
+	*gorouter.DefaultRoute[Ctx]
+	ErrorHandlerFunc func(Ctx) error
+}
+
+// GetErrorHandler returns the handler of the route. It implements
+// ErrorReturningRoute.
+func (r *GoErrorRoute[Ctx]) GetErrorHandler() func(Ctx) error {
+	return r.ErrorHandlerFunc
+}
+
+// GoRouteE creates a skeleton.Route similar to GoRoute, whose handler returns
+// an error. The error is rendered through the error handler of the server.
+//
+// ```
+//
+//	router.AddRoute(skeleton.GoRouteE("GET", "/users/:id", func(ctx *gorouter.RouteContext) error {
+//	    user, err := getUser(ctx.Params["id"])
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return skeleton.WriteJSON(ctx.W, http.StatusOK, user)
+//	}))
+//
+// ```
+func GoRouteE[Ctx any](method string, path string, fn func(ctx Ctx) error) Route[Ctx] {
+	return &GoErrorRoute[Ctx]{
+		DefaultRoute: &gorouter.DefaultRoute[Ctx]{
+			Method: method,
+			Path:   path,
+			HandlerFunc: func(ctx Ctx) {
+				_ = fn(ctx)
+			},
+		},
+		ErrorHandlerFunc: fn,
+	}
+}
+
+// GoRouteWithMetadata creates a skeleton.Route similar to GoRoute, which also
+// carries the provided metadata.
+func GoRouteWithMetadata[Ctx any](method string, path string, fn func(ctx Ctx), meta RouteMetadata) Route[Ctx] {
+	return &GoMetadataRoute[Ctx]{
+		DefaultRoute: &gorouter.DefaultRoute[Ctx]{
+			Method:      method,
+			Path:        path,
+			HandlerFunc: fn,
+		},
+		Meta: meta,
+	}
+}
+
+// GoMiddlewareRoute is a gorouter.DefaultRoute which carries its own
+// middleware. See MiddlewareRoute.
+type GoMiddlewareRoute[Ctx any] struct {
+	*gorouter.DefaultRoute[Ctx]
+	Middleware []Middleware[Ctx]
+}
+
+// Middlewares returns the middleware of the route. It implements
+// MiddlewareRoute.
+func (r *GoMiddlewareRoute[Ctx]) Middlewares() []Middleware[Ctx] {
+	return r.Middleware
+}
+
+// GoRouteWithMiddleware creates a skeleton.Route similar to GoRoute, whose
+// handler is wrapped in the provided middleware, the first being the
+// outermost.
+//
+// ```
+//
+//	router.AddRoute(skeleton.GoRouteWithMiddleware("DELETE", "/users/:id", deleteUser, requireAdmin))
+//
+// ```
+func GoRouteWithMiddleware[Ctx any](method string, path string, fn func(ctx Ctx), mw ...Middleware[Ctx]) Route[Ctx] {
+	return &GoMiddlewareRoute[Ctx]{
+		DefaultRoute: &gorouter.DefaultRoute[Ctx]{
+			Method:      method,
+			Path:        path,
+			HandlerFunc: fn,
+		},
+		Middleware: mw,
+	}
+}
+
+// GoFlaggedRoute is a gorouter.DefaultRoute which is only served while
+// Enabled returns true. See FlaggedRoute for the concurrency requirements of
+// Enabled.
+type GoFlaggedRoute[Ctx any] struct {
+	*gorouter.DefaultRoute[Ctx]
+	Enabled func() bool
+}
+
+// RouteEnabled returns the result of Enabled. It implements FlaggedRoute.
+func (r *GoFlaggedRoute[Ctx]) RouteEnabled() bool {
+	return r.Enabled()
+}
+
+// GoRouteWithFlag creates a skeleton.Route similar to GoRoute, which is only
+// served while enabled returns true.
+//
+// ```
+//
+//	var betaSearch atomic.Bool
+//	router.AddRoute(skeleton.GoRouteWithFlag("GET", "/search", search, betaSearch.Load))
+//
+// ```
+func GoRouteWithFlag[Ctx any](method string, path string, fn func(ctx Ctx), enabled func() bool) Route[Ctx] {
+	return &GoFlaggedRoute[Ctx]{
+		DefaultRoute: &gorouter.DefaultRoute[Ctx]{
+			Method:      method,
+			Path:        path,
+			HandlerFunc: fn,
+		},
+		Enabled: enabled,
+	}
+}
+
+// GoTieredRoute is a gorouter.DefaultRoute with handler variants per tier.
+// See TieredRoute.
+type GoTieredRoute[Ctx any] struct {
+	*gorouter.DefaultRoute[Ctx]
+	Variants map[string]func(Ctx)
+}
+
+// HandlerForTier returns the variant for the tier, or the default handler of
+// the route if there is none. It implements TieredRoute.
+func (r *GoTieredRoute[Ctx]) HandlerForTier(tier string) func(Ctx) {
+	if h, ok := r.Variants[tier]; ok {
+		return h
+	}
+	return r.HandlerFunc
+}
+
+// GoRouteWithTiers creates a skeleton.Route similar to GoRoute, which serves
+// the variant of the tier of the request, falling back to fn.
+func GoRouteWithTiers[Ctx any](method string, path string, fn func(ctx Ctx), variants map[string]func(Ctx)) Route[Ctx] {
+	return &GoTieredRoute[Ctx]{
+		DefaultRoute: &gorouter.DefaultRoute[Ctx]{
+			Method:      method,
+			Path:        path,
+			HandlerFunc: fn,
+		},
+		Variants: variants,
+	}
+}
+
 // GoHttpServerDelegate is a server delegate that returns a
 // gorouter.RouteContext. Note that the base gorouter.RouteContext is meant
 // to be encapsulated in another struct which is used to provide Session
@@ -108,15 +362,7 @@ type LoggingGoHttpServerDelegate struct {
 
 // RequestLogger generates a logger specific to the HTTP request.
 func (d *LoggingGoHttpServerDelegate) RequestLogger(l logger.Logger, r *http.Request) logger.HTTPRequest {
-	// Request for Google Logger
-	req := &logging.HTTPRequest{
-		Request: r,
-	}
-
-	return &logger.GoogleHTTPRequest{
-		Logger:     l,
-		LogRequest: req,
-	}
+	return googleRequestLogger(l, r)
 }
 
 // Generate generates a context to pass into the routes. The route, related
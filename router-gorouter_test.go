@@ -0,0 +1,23 @@
+package skeleton
+
+import (
+	"testing"
+
+	"github.com/cyc-ttn/gorouter"
+)
+
+func TestGoRouterRoutePattern(t *testing.T) {
+	router := GoRouter[*gorouter.RouteContext]()
+	noop := func(*gorouter.RouteContext) {}
+	if err := router.AddRoute(GoRoute("GET", "/users/:id/posts", noop)); err != nil {
+		t.Fatalf("AddRoute: %v", err)
+	}
+
+	route, err := router.Match("GET", "/users/42/posts")
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	if got := routePattern(route); got != "/users/:id/posts" {
+		t.Errorf("routePattern() = %q, want the route template %q", got, "/users/:id/posts")
+	}
+}
@@ -0,0 +1,73 @@
+package skeleton
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// RuntimeStats is a lightweight snapshot of the state of the process, for
+// quick health dashboards which do not scrape metrics.
+type RuntimeStats struct {
+	Goroutines int   `json:"goroutines"`
+	InFlight   int64 `json:"inFlight"`
+
+	HeapAlloc   uint64 `json:"heapAlloc"`
+	HeapInuse   uint64 `json:"heapInuse"`
+	HeapObjects uint64 `json:"heapObjects"`
+	Sys         uint64 `json:"sys"`
+
+	NumGC        uint32    `json:"numGC"`
+	LastGC       time.Time `json:"lastGC"`
+	PauseTotalNs uint64    `json:"pauseTotalNs"`
+}
+
+// ReadRuntimeStats returns the current RuntimeStats. inFlight, if provided,
+// reports the number of requests being served (e.g., HttpServer.InFlight).
+//
+// This calls runtime.ReadMemStats, which briefly stops the world. It is
+// cheap enough for occasional polling, but should not be called per request.
+func ReadRuntimeStats(inFlight func() int64) RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	stats := RuntimeStats{
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    m.HeapAlloc,
+		HeapInuse:    m.HeapInuse,
+		HeapObjects:  m.HeapObjects,
+		Sys:          m.Sys,
+		NumGC:        m.NumGC,
+		PauseTotalNs: m.PauseTotalNs,
+	}
+	if m.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(m.LastGC)).UTC()
+	}
+	if inFlight != nil {
+		stats.InFlight = inFlight()
+	}
+	return stats
+}
+
+// RuntimeStatsHandler returns an http.Handler which responds with the
+// current RuntimeStats as JSON. The handler is protected by guard, as the
+// stats reveal details of the process. See ReadRuntimeStats for inFlight.
+func RuntimeStatsHandler(guard *AdminGuard, inFlight func() int64) http.Handler {
+	return guard.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		_ = WriteJSON(w, http.StatusOK, ReadRuntimeStats(inFlight))
+	}))
+}
+
+// RuntimeStatsRoute creates a GET route at the provided path which responds
+// with the current RuntimeStats as JSON. It complements pprof for quick
+// checks, and should be mounted alongside the other admin endpoints.
+//
+// ```
+//
+//	router.AddRoute(skeleton.RuntimeStatsRoute("/admin/stats", guard, server.InFlight, skeleton.GoRouteContextAdapter))
+//
+// ```
+func RuntimeStatsRoute[Ctx any](path string, guard *AdminGuard, inFlight func() int64, adapter ContextAdapter[Ctx]) Route[Ctx] {
+	return GoHandlerRoute(http.MethodGet, path, RuntimeStatsHandler(guard, inFlight), adapter)
+}
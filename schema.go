@@ -0,0 +1,180 @@
+package skeleton
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidateSchema validates v, a value decoded from JSON (i.e., made of maps,
+// slices, strings, float64, bools and nil), against a JSON schema as used by
+// RouteDoc. A subset of JSON Schema is supported: type (a name or a list of
+// names), nullable, enum, properties, required, additionalProperties (as a
+// boolean) and items. Other keywords are ignored.
+//
+// The returned ValidationErrors identify the offending fields by their path
+// (e.g., items[2].name). It is nil if v is valid.
+func ValidateSchema(schema map[string]interface{}, v interface{}) ValidationErrors {
+	var errs ValidationErrors
+	validateSchema(schema, v, "", &errs)
+	return errs
+}
+
+func validateSchema(schema map[string]interface{}, v interface{}, path string, errs *ValidationErrors) {
+	if schema == nil {
+		return
+	}
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if v == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable || schemaAllowsType(schema, "null") {
+			return
+		}
+		if _, ok := schema["type"]; ok {
+			fail("must not be null")
+		}
+		return
+	}
+
+	if _, ok := schema["type"]; ok {
+		typ := jsonTypeOf(v)
+		if !schemaAllowsType(schema, typ) && !(typ == "integer" && schemaAllowsType(schema, "number")) {
+			fail("expected %s, got %s", strings.Join(schemaTypes(schema), " or "), typ)
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(normalizeJSONNumber(e), v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value is not one of the allowed values")
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		for _, name := range schemaRequired(schema) {
+			if _, ok := val[name]; !ok {
+				*errs = append(*errs, FieldError{Field: joinFieldPath(path, name), Message: "is required"})
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			propSchema, ok := props[k].(map[string]interface{})
+			if !ok {
+				if additional, isBool := schema["additionalProperties"].(bool); isBool && !additional {
+					*errs = append(*errs, FieldError{Field: joinFieldPath(path, k), Message: "is not allowed"})
+				}
+				continue
+			}
+			validateSchema(propSchema, val[k], joinFieldPath(path, k), errs)
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range val {
+			validateSchema(items, item, path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonTypeOf returns the JSON Schema type name of a decoded JSON value.
+func jsonTypeOf(v interface{}) string {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == float64(int64(val)) {
+			return "integer"
+		}
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func schemaAllowsType(schema map[string]interface{}, typ string) bool {
+	for _, t := range schemaTypes(schema) {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func schemaRequired(schema map[string]interface{}) []string {
+	switch r := schema["required"].(type) {
+	case []string:
+		return r
+	case []interface{}:
+		names := make([]string, 0, len(r))
+		for _, v := range r {
+			if s, ok := v.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// normalizeJSONNumber converts numbers declared in Go (e.g., in an enum) to
+// float64, so that they compare equal to decoded JSON numbers.
+func normalizeJSONNumber(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	}
+	return v
+}
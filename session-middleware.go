@@ -0,0 +1,55 @@
+package skeleton
+
+import (
+	"context"
+	"net/http"
+)
+
+type sessionKey struct{}
+
+// WithSession returns a copy of ctx carrying the session. The HttpServer
+// stores the session of each request in its context, so that it is
+// reachable from middleware, which only receives the Ctx of the route.
+func WithSession(ctx context.Context, sess Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// SessionFromContext returns the session stored in the context by the
+// HttpServer, or nil if there is none (e.g., the server has no
+// SessionStore).
+func SessionFromContext(ctx context.Context) Session {
+	sess, _ := ctx.Value(sessionKey{}).(Session)
+	return sess
+}
+
+// RequireSession returns a Middleware which responds with a 401, without
+// calling the handler, unless the session of the request holds a value for
+// key (e.g., the ID of the logged in user).
+//
+// The session is read from the context of the request (see
+// SessionFromContext), which is retrieved from the Ctx through the adapter.
+// The request passed to the delegate, and so to the adapter, carries the
+// session; delegates which build their Ctx from another request need to
+// preserve its context.
+//
+// ```
+//
+//	server.Group("/account", skeleton.RequireSession("user_id", skeleton.GoRouteContextAdapter))
+//
+// ```
+func RequireSession[Ctx any](key string, adapter ContextAdapter[Ctx]) Middleware[Ctx] {
+	return func(next func(Ctx)) func(Ctx) {
+		return func(ctx Ctx) {
+			w, r := adapter(ctx)
+			var sess Session
+			if r != nil {
+				sess = SessionFromContext(r.Context())
+			}
+			if sess == nil || sess.GetValue(key) == nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next(ctx)
+		}
+	}
+}
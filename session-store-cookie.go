@@ -0,0 +1,77 @@
+package skeleton
+
+import (
+	"net/http"
+
+	"github.com/gorilla/sessions"
+)
+
+// CookieSessionStore implements SessionStore by keeping sessions entirely in
+// signed (and, if an encryption key is provided, encrypted) cookies. It
+// requires no backend, which suits stateless deployments. As the values are
+// sent with every request, sessions should be kept small (browsers limit
+// cookies to about 4KB), and they cannot be revoked before they expire.
+type CookieSessionStore struct {
+	*sessions.CookieStore
+	CookieName string
+}
+
+// DefaultCookieSessionOptions returns the cookie options used by
+// NewCookieSessionStore when none are provided: the cookie is valid for 30
+// days on every path, is not readable by scripts, and is only sent on
+// same-site requests and top-level navigations.
+func DefaultCookieSessionOptions() *sessions.Options {
+	return &sessions.Options{
+		Path:     "/",
+		MaxAge:   86400 * 30,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// NewCookieSessionStore creates a CookieSessionStore. The keys are base64
+// encoded, as for PgStore; they are provided in pairs of authentication and
+// encryption keys, the latter being optional. opts provides the cookie
+// options (e.g., MaxAge, Secure, HttpOnly, SameSite); if nil,
+// DefaultCookieSessionOptions is used.
+func NewCookieSessionStore(cookieName string, opts *sessions.Options, keys ...string) (*CookieSessionStore, error) {
+	byteKeys, err := decodeSessionKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = DefaultCookieSessionOptions()
+	}
+
+	store := sessions.NewCookieStore(byteKeys...)
+	o := *opts
+	store.Options = &o
+
+	// Align the expiry of the signed values with that of the cookie.
+	store.MaxAge(o.MaxAge)
+
+	return &CookieSessionStore{
+		CookieStore: store,
+		CookieName:  cookieName,
+	}, nil
+}
+
+// Get should return the session corresponding to a single cookie, predefined
+// by the application.
+func (s *CookieSessionStore) Get(r *http.Request) (Session, error) {
+	return s.GetNamed(r, s.CookieName)
+}
+
+// GetNamed returns the session stored under the provided cookie name. It
+// implements NamedSessionStore.
+func (s *CookieSessionStore) GetNamed(r *http.Request, name string) (Session, error) {
+	sess, err := s.CookieStore.Get(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return &GorillaSession{Session: sess}, nil
+}
+
+// Shutdown should run any procedures required on shutdown. There is nothing
+// to clean up, as sessions are only stored in cookies.
+func (s *CookieSessionStore) Shutdown() {}
@@ -0,0 +1,246 @@
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/securecookie"
+)
+
+var (
+	ErrNoSessionStores = errors.New("no session stores available")
+)
+
+// DefaultFailoverRetryInterval is the default time during which a failed
+// store is skipped before it is tried again.
+const DefaultFailoverRetryInterval = 30 * time.Second
+
+// FailoverSessionStore wraps an ordered list of stores (e.g., Redis followed
+// by Postgres), so that sessions remain available while the primary store is
+// down. Get and Save use the first healthy store, and fall back to the next
+// one on failure. A store which fails is skipped for RetryInterval, after
+// which it is tried again. If every store is unhealthy, they are all tried
+// in order regardless. A session cookie which cannot be decoded is not a
+// failure of its store; a new session is started on the same store instead.
+//
+// The stores should use distinct cookie names, as a session is bound to the
+// store which created it.
+//
+// Failover trades consistency for availability. The stores are not
+// synchronized, so:
+//   - a session saved to a fallback is not visible once the primary recovers,
+//     and the primary may still hold an older version of it;
+//   - a session falls back to a new (empty) session if the store holding it
+//     is unavailable.
+//
+// Applications should therefore be able to tolerate sessions being lost or
+// reverting (e.g., by requiring users to sign in again).
+type FailoverSessionStore struct {
+	Stores []SessionStore
+
+	// RetryInterval is the time during which a failed store is skipped.
+	// Defaults to DefaultFailoverRetryInterval.
+	RetryInterval time.Duration
+
+	mu       sync.Mutex
+	failedAt map[int]time.Time
+}
+
+// NewFailoverSessionStore creates a FailoverSessionStore with the provided
+// stores, in order of preference.
+func NewFailoverSessionStore(stores ...SessionStore) *FailoverSessionStore {
+	return &FailoverSessionStore{Stores: stores}
+}
+
+func (s *FailoverSessionStore) retryInterval() time.Duration {
+	if s.RetryInterval > 0 {
+		return s.RetryInterval
+	}
+	return DefaultFailoverRetryInterval
+}
+
+// order returns the indexes of the stores starting at from, healthy stores
+// first.
+func (s *FailoverSessionStore) order(from int) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	healthy := make([]int, 0, len(s.Stores))
+	var unhealthy []int
+	for i := from; i < len(s.Stores); i++ {
+		if failedAt, ok := s.failedAt[i]; ok && time.Now().Sub(failedAt) < s.retryInterval() {
+			unhealthy = append(unhealthy, i)
+			continue
+		}
+		healthy = append(healthy, i)
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (s *FailoverSessionStore) markFailed(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failedAt == nil {
+		s.failedAt = make(map[int]time.Time)
+	}
+	s.failedAt[i] = time.Now()
+}
+
+func (s *FailoverSessionStore) markHealthy(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failedAt, i)
+}
+
+// Healthy reports, for each store, whether it is currently considered
+// healthy.
+func (s *FailoverSessionStore) Healthy() []bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	healthy := make([]bool, len(s.Stores))
+	for i := range s.Stores {
+		failedAt, ok := s.failedAt[i]
+		healthy[i] = !ok || time.Now().Sub(failedAt) >= s.retryInterval()
+	}
+	return healthy
+}
+
+// Get returns the session from the first healthy store. If every store
+// fails, the error of the last store is returned.
+func (s *FailoverSessionStore) Get(r *http.Request) (Session, error) {
+	return s.get(r, 0)
+}
+
+func (s *FailoverSessionStore) get(r *http.Request, from int) (Session, error) {
+	// The context is kept before the stores register their sessions on the
+	// request, so that a new session can be requested without the cached
+	// result.
+	ctx := r.Context()
+
+	err := ErrNoSessionStores
+	for _, i := range s.order(from) {
+		var sess Session
+		sess, err = s.Stores[i].Get(r)
+		if isSessionDecodeError(err) {
+			// A cookie which cannot be decoded (e.g., tampered with, or
+			// signed with a key which was since rotated) says nothing about
+			// the health of the store, so a new session is started on it.
+			sess, err = s.Stores[i].Get(withoutCookies(ctx, r))
+		}
+		if err != nil {
+			if !isSessionDecodeError(err) {
+				s.markFailed(i)
+			}
+			continue
+		}
+		s.markHealthy(i)
+		return &failoverSession{Session: sess, store: s, index: i}, nil
+	}
+	return nil, err
+}
+
+// isSessionDecodeError returns true if err reports a session cookie which
+// could not be decoded, rather than a failure of the store.
+func isSessionDecodeError(err error) bool {
+	var cookieErr securecookie.Error
+	return errors.As(err, &cookieErr) && cookieErr.IsDecode()
+}
+
+// withoutCookies returns a copy of the request with the provided context and
+// without cookies, from which stores create new sessions.
+func withoutCookies(ctx context.Context, r *http.Request) *http.Request {
+	r = r.Clone(ctx)
+	r.Header.Del("Cookie")
+	return r
+}
+
+// Ping checks every store implementing PingableSessionStore, updating its
+// health. It returns an error only if no store is healthy, as the server can
+// still serve sessions through the remaining stores.
+func (s *FailoverSessionStore) Ping(ctx context.Context) error {
+	err := ErrNoSessionStores
+	ok := false
+	for i, store := range s.Stores {
+		p, pingable := store.(PingableSessionStore)
+		if !pingable {
+			ok = true
+			continue
+		}
+		if pErr := p.Ping(ctx); pErr != nil {
+			s.markFailed(i)
+			err = pErr
+			continue
+		}
+		s.markHealthy(i)
+		ok = true
+	}
+	if ok {
+		return nil
+	}
+	return err
+}
+
+// Shutdown shuts down every store.
+func (s *FailoverSessionStore) Shutdown() {
+	for _, store := range s.Stores {
+		store.Shutdown()
+	}
+}
+
+// failoverSession is a session of a FailoverSessionStore. If saving to its
+// store fails, the values are copied to a session of the next store, which
+// is saved instead.
+type failoverSession struct {
+	Session
+	store *FailoverSessionStore
+	index int
+	keys  []string
+}
+
+func (s *failoverSession) SetValue(key string, val interface{}) {
+	s.keys = append(s.keys, key)
+	s.Session.SetValue(key, val)
+}
+
+func (s *failoverSession) Save(r *http.Request, w http.ResponseWriter) error {
+	err := s.Session.Save(r, w)
+	if err == nil {
+		return nil
+	}
+	s.store.markFailed(s.index)
+
+	if s.index+1 >= len(s.store.Stores) {
+		return err
+	}
+	next, gErr := s.store.get(r, s.index+1)
+	if gErr != nil {
+		return err
+	}
+	fs := next.(*failoverSession)
+	copySessionValues(s.Session, fs.Session, s.keys)
+	if err := fs.Save(r, w); err != nil {
+		return err
+	}
+	*s = *fs
+	return nil
+}
+
+// copySessionValues copies the values of one session into another. All
+// values are copied between gorilla sessions; otherwise only the provided
+// keys are.
+func copySessionValues(from, to Session, keys []string) {
+	fromG, ok1 := from.(*GorillaSession)
+	toG, ok2 := to.(*GorillaSession)
+	if ok1 && ok2 {
+		for k, v := range fromG.Values {
+			toG.Values[k] = v
+		}
+		return
+	}
+	for _, k := range keys {
+		to.SetValue(k, from.GetValue(k))
+	}
+}
@@ -0,0 +1,40 @@
+package skeleton
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/securecookie"
+)
+
+func TestFailoverSessionStoreDecodeError(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32))
+	primary, err := NewCookieSessionStore("primary", nil, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondary, err := NewCookieSessionStore("secondary", nil, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewFailoverSessionStore(primary, secondary)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "primary", Value: "tampered"})
+
+	sess, err := store.Get(r)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !sess.IsNew() {
+		t.Error("a session whose cookie cannot be decoded should be new")
+	}
+	if got := sess.(*failoverSession).index; got != 0 {
+		t.Errorf("session served by store %d, want 0", got)
+	}
+	if healthy := store.Healthy(); !healthy[0] {
+		t.Error("a decode error should not mark the store as failed")
+	}
+}
@@ -0,0 +1,195 @@
+package skeleton
+
+import (
+	"encoding/base32"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+// MemorySessionStore implements SessionStore by keeping sessions in memory.
+// The cookie only holds the signed ID of the session. It is intended for
+// local development, tests and small single-instance deployments: sessions
+// are lost when the process exits and are not shared between instances.
+//
+// Saved values are kept for the lifetime of the process, or until the
+// session expires according to its MaxAge. Expired sessions are removed
+// every DefaultMemorySessionCleanupInterval by a goroutine, which is
+// stopped by Shutdown.
+type MemorySessionStore struct {
+	CookieName string
+
+	// Options are the default cookie options of new sessions.
+	Options *sessions.Options
+
+	codecs   []securecookie.Codec
+	mu       sync.Mutex
+	sessions map[string]memorySession
+
+	stopCleanup     chan struct{}
+	stopCleanupOnce sync.Once
+}
+
+// DefaultMemorySessionCleanupInterval is the interval at which
+// MemorySessionStore removes expired sessions.
+const DefaultMemorySessionCleanupInterval = 5 * time.Minute
+
+type memorySession struct {
+	values  map[interface{}]interface{}
+	expires time.Time
+}
+
+// NewMemorySessionStore creates a MemorySessionStore. The arguments match
+// PgStore, so that it can be used as a drop-in replacement.
+func NewMemorySessionStore(cookieName string, keys ...string) (*MemorySessionStore, error) {
+	byteKeys, err := decodeSessionKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	s := &MemorySessionStore{
+		CookieName: cookieName,
+		Options: &sessions.Options{
+			Path:   "/",
+			MaxAge: 86400 * 30,
+		},
+		codecs:      securecookie.CodecsFromPairs(byteKeys...),
+		sessions:    make(map[string]memorySession),
+		stopCleanup: make(chan struct{}),
+	}
+	go s.cleanup(DefaultMemorySessionCleanupInterval)
+	return s, nil
+}
+
+// cleanup removes expired sessions every interval, until Shutdown is called.
+func (s *MemorySessionStore) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCleanup:
+			return
+		case <-ticker.C:
+			s.removeExpired(time.Now())
+		}
+	}
+}
+
+// removeExpired removes the sessions which expired before now.
+func (s *MemorySessionStore) removeExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, stored := range s.sessions {
+		if !stored.expires.IsZero() && now.After(stored.expires) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// Get should return the session corresponding to a single cookie, predefined
+// by the application.
+func (s *MemorySessionStore) Get(r *http.Request) (Session, error) {
+	return s.GetNamed(r, s.CookieName)
+}
+
+// GetNamed returns the session stored under the provided cookie name. It
+// implements NamedSessionStore.
+func (s *MemorySessionStore) GetNamed(r *http.Request, name string) (Session, error) {
+	sess, err := sessions.GetRegistry(r).Get((*memoryGorillaStore)(s), name)
+	if err != nil {
+		return nil, err
+	}
+	return &GorillaSession{Session: sess}, nil
+}
+
+// Shutdown should run any procedures required on shutdown. The cleanup of
+// expired sessions is stopped, and the sessions are discarded.
+func (s *MemorySessionStore) Shutdown() {
+	s.stopCleanupOnce.Do(func() {
+		if s.stopCleanup != nil {
+			close(s.stopCleanup)
+		}
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]memorySession)
+}
+
+// memoryGorillaStore implements sessions.Store on top of MemorySessionStore.
+// It is a separate type because the Get methods of both interfaces clash.
+type memoryGorillaStore MemorySessionStore
+
+func (s *memoryGorillaStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+func (s *memoryGorillaStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	c, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	if err := securecookie.DecodeMulti(name, c.Value, &session.ID, s.codecs...); err != nil {
+		return session, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.sessions[session.ID]
+	if !ok || (!stored.expires.IsZero() && time.Now().After(stored.expires)) {
+		// Unknown (e.g., after a restart) or expired; start over.
+		delete(s.sessions, session.ID)
+		session.ID = ""
+		return session, nil
+	}
+	for k, v := range stored.values {
+		session.Values[k] = v
+	}
+	session.IsNew = false
+	return session, nil
+}
+
+func (s *memoryGorillaStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	// A negative MaxAge deletes the session.
+	if session.Options.MaxAge < 0 {
+		s.mu.Lock()
+		delete(s.sessions, session.ID)
+		s.mu.Unlock()
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if session.ID == "" {
+		session.ID = strings.TrimRight(
+			base32.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32)), "=")
+	}
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.codecs...)
+	if err != nil {
+		return err
+	}
+
+	stored := memorySession{
+		values: make(map[interface{}]interface{}, len(session.Values)),
+	}
+	for k, v := range session.Values {
+		stored.values[k] = v
+	}
+	if session.Options.MaxAge > 0 {
+		stored.expires = time.Now().Add(time.Duration(session.Options.MaxAge) * time.Second)
+	}
+
+	s.mu.Lock()
+	s.sessions[session.ID] = stored
+	s.mu.Unlock()
+
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
@@ -0,0 +1,42 @@
+package skeleton
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/gorilla/securecookie"
+)
+
+func TestMemorySessionStoreRemoveExpired(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32))
+	store, err := NewMemorySessionStore("session", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Shutdown()
+
+	now := time.Now()
+	store.sessions["expired"] = memorySession{expires: now.Add(-time.Minute)}
+	store.sessions["valid"] = memorySession{expires: now.Add(time.Minute)}
+	store.sessions["session"] = memorySession{}
+
+	store.removeExpired(now)
+
+	if _, ok := store.sessions["expired"]; ok {
+		t.Error("expired session was not removed")
+	}
+	if len(store.sessions) != 2 {
+		t.Errorf("%d sessions remain, want 2", len(store.sessions))
+	}
+}
+
+func TestMemorySessionStoreShutdownTwice(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32))
+	store, err := NewMemorySessionStore("session", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Shutdown()
+	store.Shutdown()
+}
@@ -0,0 +1,103 @@
+package skeleton
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DefaultUserIndexTable is the table used by PgSessionStore to index
+// sessions by user.
+const DefaultUserIndexTable = "http_session_users"
+
+// pgSessionsTable is the table in which pgstore stores sessions.
+const pgSessionsTable = "http_sessions"
+
+// UserSessionInvalidator is an optional interface for session stores which
+// are able to invalidate every session of a user (e.g., to log out
+// everywhere after a password change).
+type UserSessionInvalidator interface {
+	InvalidateUser(ctx context.Context, userID string) error
+}
+
+// EnableUserIndex maintains an index from users to their sessions, which is
+// required by InvalidateUser. The index table is created if it does not
+// exist.
+//
+// The index is maintained on Save: if the session holds a value under
+// userIDKey, the session is indexed under that user (formatted with
+// fmt.Sprint). If the value has been removed (e.g., on logout) or the session
+// is deleted (a negative MaxAge), the session is removed from the index.
+// Sessions saved before the index was enabled are not indexed until they are
+// next saved. Entries of expired sessions are left behind until the user is
+// invalidated, which is harmless.
+func (s *PgSessionStore) EnableUserIndex(ctx context.Context, userIDKey string) error {
+	if s.UserIndexTable == "" {
+		s.UserIndexTable = DefaultUserIndexTable
+	}
+	_, err := s.Store.DbPool.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %[1]s (
+			session_id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS %[1]s_user_id_idx ON %[1]s (user_id);
+	`, s.UserIndexTable))
+	if err != nil {
+		return err
+	}
+	s.UserIDKey = userIDKey
+	return nil
+}
+
+// InvalidateUser deletes every indexed session of the user. It implements
+// UserSessionInvalidator. EnableUserIndex needs to be called beforehand.
+func (s *PgSessionStore) InvalidateUser(ctx context.Context, userID string) error {
+	if s.UserIDKey == "" {
+		return NewSessionError("unable to invalidate user", fmt.Errorf("user index is not enabled"))
+	}
+
+	tx, err := s.Store.DbPool.BeginTx(ctx, nil)
+	if err != nil {
+		return NewSessionError("unable to invalidate user", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM %s WHERE key IN (SELECT session_id FROM %s WHERE user_id = $1)`,
+		pgSessionsTable, s.UserIndexTable,
+	), userID); err != nil {
+		return NewSessionError("unable to invalidate user", err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM %s WHERE user_id = $1`, s.UserIndexTable,
+	), userID); err != nil {
+		return NewSessionError("unable to invalidate user", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return NewSessionError("unable to invalidate user", err)
+	}
+	return nil
+}
+
+// indexSession updates the user index for a session which has just been
+// saved.
+func (s *PgSessionStore) indexSession(r *http.Request, sess *GorillaSession) error {
+	if sess.ID == "" {
+		return nil
+	}
+	ctx := r.Context()
+
+	userID := sess.Values[s.UserIDKey]
+	if userID == nil || (sess.Options != nil && sess.Options.MaxAge < 0) {
+		_, err := s.Store.DbPool.ExecContext(ctx, fmt.Sprintf(
+			`DELETE FROM %s WHERE session_id = $1`, s.UserIndexTable,
+		), sess.ID)
+		return err
+	}
+
+	_, err := s.Store.DbPool.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (session_id, user_id) VALUES ($1, $2)
+		ON CONFLICT (session_id) DO UPDATE SET user_id = EXCLUDED.user_id
+	`, s.UserIndexTable), sess.ID, fmt.Sprint(userID))
+	return err
+}
@@ -1,6 +1,7 @@
 package skeleton
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -16,11 +17,42 @@ import (
 type PgSessionStore struct {
 	CookieName string
 	Store      *pgstore.PGStore
+
+	// SessionVersion is the current version of the format of the session
+	// data. When greater than 0, sessions with an older version are passed
+	// through MigrateSession on Get.
+	SessionVersion int
+	MigrateSession SessionMigrator
+
+	// OnNewSession, if provided, is called when a brand-new session is
+	// created, allowing default values (e.g., a visitor ID or locale) to be
+	// seeded once. Values set here are persisted on the next Save.
+	OnNewSession func(s Session, r *http.Request)
+
+	// UserIDKey and UserIndexTable configure the index of sessions by user,
+	// used by InvalidateUser. Use EnableUserIndex to set them up.
+	UserIDKey      string
+	UserIndexTable string
 }
 
 // GorillaSession wraps gorilla's session so that it implements Session.
 type GorillaSession struct {
 	*sessions.Session
+
+	// afterSave, if set, is called once the session has been saved.
+	afterSave func(r *http.Request, s *GorillaSession) error
+}
+
+// Save saves the session. It overrides the Save of the gorilla session so
+// that the store can maintain its indexes.
+func (s *GorillaSession) Save(r *http.Request, w http.ResponseWriter) error {
+	if err := s.Session.Save(r, w); err != nil {
+		return err
+	}
+	if s.afterSave != nil {
+		return s.afterSave(r, s)
+	}
+	return nil
 }
 
 // SetValue sets a value in the session. The value does not be come
@@ -34,13 +66,81 @@ func (s *GorillaSession) GetValue(key string) interface{} {
 	return s.Values[key]
 }
 
+// IsNew returns true if the session was created by the current request. It
+// shadows the IsNew field of the gorilla session, which it reports.
+func (s *GorillaSession) IsNew() bool {
+	return s.Session.IsNew
+}
+
+// AddFlash adds a flash message to the session. The message does not become
+// permanent until Save is called.
+func (s *GorillaSession) AddFlash(value interface{}, vars ...string) {
+	s.Session.AddFlash(value, vars...)
+}
+
+// Flashes returns the flash messages of the session, and removes them. The
+// removal does not become permanent until Save is called.
+func (s *GorillaSession) Flashes(vars ...string) []interface{} {
+	return s.Session.Flashes(vars...)
+}
+
+// Version returns the version of the format of the session data. Sessions
+// without a version return 0.
+func (s *GorillaSession) Version() int {
+	switch v := s.Values[SessionVersionKey].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// SetVersion sets the version of the format of the session data. The value
+// does not become permanent until Save is called.
+func (s *GorillaSession) SetVersion(v int) {
+	s.Values[SessionVersionKey] = v
+}
+
+// SetCookieOptions overrides the cookie options (e.g., SameSite, MaxAge,
+// Secure) used when the session is next saved. This allows a single
+// application to issue different cookie policies per flow, such as
+// SameSite=Lax for OAuth redirects and SameSite=None for embedded widgets.
+//
+// Options set here take precedence over the defaults of the store. The store
+// defaults are copied into the session when it is retrieved, so they only
+// apply if SetCookieOptions is not called before Save. The provided options
+// are copied, so later changes to opts do not affect the session.
+func (s *GorillaSession) SetCookieOptions(opts sessions.Options) {
+	s.Options = &opts
+}
+
 // PgStore wraps `pgstore` so that it satisfies SessionStore.
 func PgStore(db *sqlx.DB, cookieName string, keys ...string) (*PgSessionStore, error) {
+	byteKeys, err := decodeSessionKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := pgstore.NewPGStoreFromPool(db.DB, byteKeys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PgSessionStore{
+		Store:      store,
+		CookieName: cookieName,
+	}, nil
+}
+
+// decodeSessionKeys base64 decodes the keys provided to a session store.
+func decodeSessionKeys(keys []string) ([][]byte, error) {
 	if len(keys) < 1 {
 		return nil, errors.New("sessions requires at least one key")
 	}
 
-	// Base64 decode the session key.
 	byteKeys := make([][]byte, 0, len(keys))
 	for _, k := range keys {
 		b, err := base64.StdEncoding.DecodeString(k)
@@ -49,26 +149,41 @@ func PgStore(db *sqlx.DB, cookieName string, keys ...string) (*PgSessionStore, e
 		}
 		byteKeys = append(byteKeys, b)
 	}
-
-	store, err := pgstore.NewPGStoreFromPool(db.DB, byteKeys...)
-	if err != nil {
-		return nil, err
-	}
-
-	return &PgSessionStore{
-		Store:      store,
-		CookieName: cookieName,
-	}, nil
+	return byteKeys, nil
 }
 
 // Get should return the session corresponding to a single cookie, predefined
 // by the application.
 func (s *PgSessionStore) Get(r *http.Request) (Session, error) {
-	sess, err := s.Store.Get(r, s.CookieName)
+	return s.GetNamed(r, s.CookieName)
+}
+
+// GetNamed returns the session stored under the provided cookie name. It
+// implements NamedSessionStore.
+func (s *PgSessionStore) GetNamed(r *http.Request, name string) (Session, error) {
+	sess, err := s.Store.Get(r, name)
 	if err != nil {
 		return nil, err
 	}
-	return &GorillaSession{Session: sess}, nil
+	gs := &GorillaSession{Session: sess}
+	if s.UserIDKey != "" {
+		gs.afterSave = s.indexSession
+	}
+	if s.SessionVersion > 0 {
+		if err := migrateSession(gs, gs.IsNew(), s.SessionVersion, s.MigrateSession); err != nil {
+			return nil, err
+		}
+	}
+	if gs.IsNew() && s.OnNewSession != nil {
+		s.OnNewSession(gs, r)
+	}
+	return gs, nil
+}
+
+// Ping checks that the database backing the store is reachable. It
+// implements PingableSessionStore.
+func (s *PgSessionStore) Ping(ctx context.Context) error {
+	return s.Store.DbPool.PingContext(ctx)
 }
 
 // Shutdown should run any procedures required on shutdown.
@@ -1,6 +1,7 @@
 package skeleton
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -18,10 +19,49 @@ type SessionStore interface {
 	Shutdown()
 }
 
+// NamedSessionStore is an optional interface for session stores which can
+// load sessions from cookies other than their default one, allowing several
+// independent sessions per request. See HttpServer.SessionName.
+type NamedSessionStore interface {
+	SessionStore
+
+	// GetNamed is a version of Get which returns the session stored under
+	// the provided cookie name.
+	GetNamed(r *http.Request, name string) (Session, error)
+}
+
+// PingableSessionStore is an optional interface for session stores which are
+// able to report the health of their backend (e.g., by pinging the
+// database).
+type PingableSessionStore interface {
+	SessionStore
+
+	// Ping returns an error if the backend of the store is unreachable.
+	Ping(ctx context.Context) error
+}
+
+// SessionStoreHealthCheck returns a HealthCheck for the provided store, so
+// that the health of the session backend is part of the readiness of the
+// server. Stores which do not implement PingableSessionStore are always
+// considered healthy.
+func SessionStoreHealthCheck(store SessionStore) HealthCheck {
+	return HealthCheck{
+		Name: "session-store",
+		Check: func(ctx context.Context) error {
+			if p, ok := store.(PingableSessionStore); ok {
+				return p.Ping(ctx)
+			}
+			return nil
+		},
+	}
+}
+
 // Session describes a singular session object. For example, this could store
 // the UserID related to a cookie.
 type Session interface {
-	// Save completes the session operation.
+	// Save completes the session operation. As it may set a cookie, it must
+	// be called before the response is written or the connection is
+	// hijacked (e.g., upgraded to a WebSocket).
 	Save(r *http.Request, w http.ResponseWriter) error
 
 	// SetValue sets a value in the session. The value does not be come
@@ -30,4 +70,18 @@ type Session interface {
 
 	// GetValue returns a value from the session.
 	GetValue(key string) interface{}
+
+	// IsNew returns true if the session was created by the current request,
+	// as opposed to being loaded from an existing cookie.
+	IsNew() bool
+
+	// AddFlash adds a flash message to the session, which is removed once
+	// read through Flashes (e.g., a notice shown after a redirect). The key
+	// of the flashes can be provided in vars; a default key is used
+	// otherwise. As with SetValue, Save needs to be called.
+	AddFlash(value interface{}, vars ...string)
+
+	// Flashes returns the flash messages of the session, and removes them.
+	// Save needs to be called for the removal to be permanent.
+	Flashes(vars ...string) []interface{}
 }
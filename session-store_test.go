@@ -0,0 +1,49 @@
+package skeleton
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pingSessionStore is a session store whose Ping returns err.
+type pingSessionStore struct {
+	SessionStore
+	err error
+}
+
+func (s *pingSessionStore) Ping(ctx context.Context) error {
+	return s.err
+}
+
+func TestSessionStoreHealthCheckUnhealthy(t *testing.T) {
+	errDown := errors.New("connection refused")
+	check := SessionStoreHealthCheck(&pingSessionStore{err: errDown})
+
+	if err := check.Check(context.Background()); !errors.Is(err, errDown) {
+		t.Errorf("Check() = %v, want %v", err, errDown)
+	}
+
+	rec := httptest.NewRecorder()
+	ReadinessHandler(check).ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	var res healthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Failed) != 1 || res.Failed[0] != check.Name {
+		t.Errorf("failed checks = %v, want [%s]", res.Failed, check.Name)
+	}
+}
+
+func TestSessionStoreHealthCheckNotPingable(t *testing.T) {
+	check := SessionStoreHealthCheck(&CookieSessionStore{})
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("Check() = %v, want stores without Ping to be healthy", err)
+	}
+}
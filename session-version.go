@@ -0,0 +1,45 @@
+package skeleton
+
+// SessionVersionKey is the key under which the version of the format of the
+// session data is stored.
+const SessionVersionKey = "_skeleton_session_version"
+
+// VersionedSession is implemented by sessions which are able to store the
+// version of the format of their data.
+type VersionedSession interface {
+	Session
+
+	// Version returns the version of the format of the session data. Sessions
+	// created before versioning was introduced return 0.
+	Version() int
+
+	// SetVersion sets the version of the format of the session data. Similar
+	// to SetValue, it does not become permanent until Save is called.
+	SetVersion(v int)
+}
+
+// SessionMigrator upgrades the data of a session from fromVersion to the
+// current format. It is called when a session is retrieved with a version
+// older than the current one. After the migrator succeeds, the session is
+// marked with the current version; it is persisted on the next Save.
+type SessionMigrator func(s Session, fromVersion int) error
+
+// migrateSession brings the session up to the provided version. New sessions
+// are marked with the current version without being migrated. If migrate is
+// nil, existing sessions are left untouched.
+func migrateSession(s VersionedSession, isNew bool, current int, migrate SessionMigrator) error {
+	if isNew {
+		s.SetVersion(current)
+		return nil
+	}
+
+	from := s.Version()
+	if from >= current || migrate == nil {
+		return nil
+	}
+	if err := migrate(s, from); err != nil {
+		return NewSessionError("unable to migrate session", err)
+	}
+	s.SetVersion(current)
+	return nil
+}
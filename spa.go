@@ -0,0 +1,116 @@
+package skeleton
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultSPAIndex is the file served by SPA for paths which do not map to a
+// file.
+const DefaultSPAIndex = "index.html"
+
+// DefaultSPANotFoundPrefixes are the path prefixes under which missing files
+// result in a 404 when SPA.NotFoundPrefixes is not set.
+var DefaultSPANotFoundPrefixes = []string{"/assets/"}
+
+// SPA serves a built single-page application (e.g., from an embed.FS or
+// os.DirFS). Paths which map to a file are served as is; any other path is
+// served the index file, so that client-side routing works when a page is
+// loaded directly. Missing files under NotFoundPrefixes result in a 404, so
+// that a broken asset reference is not answered with HTML.
+//
+// SPA is intended to be used as HttpServer.Fallback, so that the routes of
+// the API take precedence:
+//
+// ```
+//
+//	dist, _ := fs.Sub(embeddedFiles, "dist")
+//	server.Fallback = skeleton.NewSPA(dist)
+//
+// ```
+type SPA struct {
+	FS fs.FS
+
+	// Index is the file served for paths which do not map to a file.
+	// Defaults to DefaultSPAIndex.
+	Index string
+
+	// NotFoundPrefixes are the path prefixes under which missing files
+	// result in a 404 (e.g., /assets/ or /api/). Defaults to
+	// DefaultSPANotFoundPrefixes.
+	NotFoundPrefixes []string
+}
+
+// NewSPA creates an SPA serving the files of fsys.
+func NewSPA(fsys fs.FS) *SPA {
+	return &SPA{FS: fsys}
+}
+
+func (s *SPA) index() string {
+	if s.Index != "" {
+		return s.Index
+	}
+	return DefaultSPAIndex
+}
+
+func (s *SPA) notFoundPrefixes() []string {
+	if s.NotFoundPrefixes != nil {
+		return s.NotFoundPrefixes
+	}
+	return DefaultSPANotFoundPrefixes
+}
+
+// ServeHTTP serves the file of the request, or the index file. Only GET and
+// HEAD requests are served; other requests result in a 404, as they are
+// expected to target the API.
+func (s *SPA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Resolve the path as a file path rooted at /, which cannot escape the
+	// file system through dot-dot segments.
+	full, err := SafeFilePath("/", r.URL.Path)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	p := filepath.ToSlash(full)
+	name := strings.TrimPrefix(p, "/")
+	if name != "" && name != s.index() {
+		if info, err := fs.Stat(s.FS, name); err == nil && !info.IsDir() {
+			http.FileServer(http.FS(s.FS)).ServeHTTP(w, r)
+			return
+		}
+		for _, prefix := range s.notFoundPrefixes() {
+			if strings.HasPrefix(p+"/", prefix) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+	}
+	s.serveIndex(w, r)
+}
+
+// serveIndex serves the index file. It is not cached, so that clients pick
+// up new builds.
+func (s *SPA) serveIndex(w http.ResponseWriter, r *http.Request) {
+	b, err := fs.ReadFile(s.FS, s.index())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, s.index(), time.Time{}, bytes.NewReader(b))
+}
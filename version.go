@@ -0,0 +1,80 @@
+package skeleton
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata which can be set at link time. For example:
+//
+// ```
+// go build -ldflags "-X github.com/cyc-ttn/skeleton.Version=v1.2.3"
+// ```
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+// VersionInfo describes the build which is currently running.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+// ReadVersionInfo returns the VersionInfo of the running binary. Values set
+// through -ldflags take precedence. Missing values are populated from
+// debug.ReadBuildInfo where possible. GoVersion is always populated from
+// runtime.Version.
+func ReadVersionInfo() VersionInfo {
+	info := VersionInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = setting.Value
+		case setting.Key == "vcs.time" && info.BuildTime == "":
+			info.BuildTime = setting.Value
+		}
+	}
+	return info
+}
+
+// VersionHandler returns an http.Handler which responds with the provided
+// VersionInfo as JSON. GoVersion is populated from runtime.Version if not
+// set.
+func VersionHandler(info VersionInfo) http.Handler {
+	if info.GoVersion == "" {
+		info.GoVersion = runtime.Version()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = WriteJSON(w, http.StatusOK, info)
+	})
+}
+
+// VersionRoute creates a GET route at the provided path which responds with
+// the provided VersionInfo as JSON.
+//
+// ```
+//
+//	router.AddRoute(skeleton.VersionRoute("/version", skeleton.ReadVersionInfo(), skeleton.GoRouteContextAdapter))
+//
+// ```
+func VersionRoute[Ctx any](path string, info VersionInfo, adapter ContextAdapter[Ctx]) Route[Ctx] {
+	return GoHandlerRoute(http.MethodGet, path, VersionHandler(info), adapter)
+}